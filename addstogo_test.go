@@ -15,56 +15,8 @@ func TestUnmarshalStationsInfo(t *testing.T) {
 		}{Name: "stations"}, Request: struct {
 			Type string "xml:\"type,attr\""
 		}{Type: "retrieve"}, Errors: []string(nil), Warnings: []string(nil), NumResults: 0, TimeTakenMs: 5, Data: struct {
-			Station []struct {
-				StationID  string   `xml:"station_id"`
-				Latitude   float32  `xml:"latitude"`
-				Longitude  float32  `xml:"longitude"`
-				ElevationM float32  `xml:"elevation_m"`
-				Site       string   `xml:"site"`
-				Country    string   `xml:"country"`
-				SiteType   siteType `xml:"site_type,omitempty"`
-			} "xml:\"Station\""
-		}{Station: []struct {
-			StationID  string   `xml:"station_id"`
-			Latitude   float32  `xml:"latitude"`
-			Longitude  float32  `xml:"longitude"`
-			ElevationM float32  `xml:"elevation_m"`
-			Site       string   `xml:"site"`
-			Country    string   `xml:"country"`
-			SiteType   siteType `xml:"site_type,omitempty"`
-		}{struct {
-			StationID  string   `xml:"station_id"`
-			Latitude   float32  `xml:"latitude"`
-			Longitude  float32  `xml:"longitude"`
-			ElevationM float32  `xml:"elevation_m"`
-			Site       string   `xml:"site"`
-			Country    string   `xml:"country"`
-			SiteType   siteType `xml:"site_type,omitempty"`
-		}{StationID: "KDEN", Latitude: 39.85, Longitude: -104.65, ElevationM: 1640, Site: "DENVER (DIA)", Country: "US", SiteType: siteType{METAR: true, TAF: false, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, struct {
-			StationID  string   `xml:"station_id"`
-			Latitude   float32  `xml:"latitude"`
-			Longitude  float32  `xml:"longitude"`
-			ElevationM float32  `xml:"elevation_m"`
-			Site       string   `xml:"site"`
-			Country    string   `xml:"country"`
-			SiteType   siteType `xml:"site_type,omitempty"`
-		}{StationID: "KSEA", Latitude: 47.45, Longitude: -122.32, ElevationM: 136, Site: "SEATTLE/METRO", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, struct {
-			StationID  string   `xml:"station_id"`
-			Latitude   float32  `xml:"latitude"`
-			Longitude  float32  `xml:"longitude"`
-			ElevationM float32  `xml:"elevation_m"`
-			Site       string   `xml:"site"`
-			Country    string   `xml:"country"`
-			SiteType   siteType `xml:"site_type,omitempty"`
-		}{StationID: "PHNL", Latitude: 21.33, Longitude: -157.92, ElevationM: 4, Site: "HONOLULU", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, struct {
-			StationID  string   `xml:"station_id"`
-			Latitude   float32  `xml:"latitude"`
-			Longitude  float32  `xml:"longitude"`
-			ElevationM float32  `xml:"elevation_m"`
-			Site       string   `xml:"site"`
-			Country    string   `xml:"country"`
-			SiteType   siteType `xml:"site_type,omitempty"`
-		}{StationID: "KABR", Latitude: 45.45, Longitude: -98.42, ElevationM: 397, Site: "ABERDEEN", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: true, NEXRAD: true, Rawinsonde: true, WindProfiler: false}}}}}
+			Station []Station "xml:\"Station\""
+		}{Station: []Station{Station{StationID: "KDEN", WMOID: "72565", Latitude: 39.85, Longitude: -104.65, ElevationM: 1640, Site: "DENVER (DIA)", State: "CO", Country: "US", SiteType: siteType{METAR: true, TAF: false, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, Station{StationID: "KSEA", WMOID: "72793", Latitude: 47.45, Longitude: -122.32, ElevationM: 136, Site: "SEATTLE/METRO", State: "WA", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, Station{StationID: "PHNL", WMOID: "91182", Latitude: 21.33, Longitude: -157.92, ElevationM: 4, Site: "HONOLULU", State: "HI", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: false, NEXRAD: false, Rawinsonde: false, WindProfiler: false}}, Station{StationID: "KABR", WMOID: "72659", Latitude: 45.45, Longitude: -98.42, ElevationM: 397, Site: "ABERDEEN", State: "SD", Country: "US", SiteType: siteType{METAR: true, TAF: true, WFOoffice: true, NEXRAD: true, Rawinsonde: true, WindProfiler: false}}}}}
 
 		si, err := UnmarshalStationsInfo(input)
 		Convey("struct should be builded correctly", func() {
@@ -84,511 +36,28 @@ func TestUnmarshalTafs(t *testing.T) {
 		}{Name: "tafs"}, Request: struct {
 			Type string "xml:\"type,attr\""
 		}{Type: "retrieve"}, Errors: []string(nil), Warnings: []string(nil), TimeTakenMs: 9, Data: struct {
-			TAF []struct {
-				RawText       string    "xml:\"raw_text\""
-				StationID     string    "xml:\"station_id\""
-				IssueTime     time.Time "xml:\"issue_time\""
-				BulletinTime  time.Time "xml:\"bulletin_time\""
-				ValidTimeFrom time.Time "xml:\"valid_time_from\""
-				ValidTimeTo   time.Time "xml:\"valid_time_to\""
-				Remarks       string    "xml:\"remarks\""
-				Latitude      float32   "xml:\"latitude\""
-				Longitude     float32   "xml:\"longitude\""
-				ElevationM    float32   "xml:\"elevation_m\""
-				Forecast      []struct {
-					FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-					FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-					ChangeIndicator     string    "xml:\"change_indicator\""
-					TimeBecoming        time.Time "xml:\"time_becoming\""
-					Probability         string    "xml:\"probability\""
-					WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-					WindSpeedKt         int       "xml:\"wind_speed_kt\""
-					WindGustKt          int       "xml:\"wind_gust_kt\""
-					WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-					WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-					WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-					VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-					AltimInHg           float32   "xml:\"altim_in_hg\""
-					VertVisFt           int       "xml:\"vert_vis_ft\""
-					WxString            string    "xml:\"wx_string\""
-					NotDecoded          string    "xml:\"not_decoded\""
-					SkyCondition        []struct {
-						SkyCover       string "xml:\"sky_cover,attr\""
-						CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-						CloudType      string "xml:\"cloud_type,attr\""
-					} "xml:\"sky_condition\""
-					TurbulenceCondition []struct {
-						TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-						TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-						TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-					} "xml:\"turbulence_condition\""
-					IcingCondition []struct {
-						IcingIntensity   string "xml:\"icing_intensity,attr\""
-						IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-						IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-					} "xml:\"icing_condition\""
-					Temperature []struct {
-						ValidTime time.Time "xml:\"valid_time,omitempty\""
-						SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-						MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-						MinTempC  string    "xml:\"min_temp_c,omitempty\""
-					} "xml:\"temperature,omitempty\""
-				} "xml:\"forecast\""
-			} "xml:\"TAF\""
-			NumResults int "xml:\"num_results,attr\""
-		}{TAF: []struct {
-			RawText       string    "xml:\"raw_text\""
-			StationID     string    "xml:\"station_id\""
-			IssueTime     time.Time "xml:\"issue_time\""
-			BulletinTime  time.Time "xml:\"bulletin_time\""
-			ValidTimeFrom time.Time "xml:\"valid_time_from\""
-			ValidTimeTo   time.Time "xml:\"valid_time_to\""
-			Remarks       string    "xml:\"remarks\""
-			Latitude      float32   "xml:\"latitude\""
-			Longitude     float32   "xml:\"longitude\""
-			ElevationM    float32   "xml:\"elevation_m\""
-			Forecast      []struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			} "xml:\"forecast\""
-		}{struct {
-			RawText       string    "xml:\"raw_text\""
-			StationID     string    "xml:\"station_id\""
-			IssueTime     time.Time "xml:\"issue_time\""
-			BulletinTime  time.Time "xml:\"bulletin_time\""
-			ValidTimeFrom time.Time "xml:\"valid_time_from\""
-			ValidTimeTo   time.Time "xml:\"valid_time_to\""
-			Remarks       string    "xml:\"remarks\""
-			Latitude      float32   "xml:\"latitude\""
-			Longitude     float32   "xml:\"longitude\""
-			ElevationM    float32   "xml:\"elevation_m\""
-			Forecast      []struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			} "xml:\"forecast\""
-		}{RawText: "TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040 BECMG 0708/0709 28006G11MPS SCT030CB TEMPO 0709/0717 -TSRA BECMG 0717/0718 05005MPS BKN011 TEMPO 0718/0806 VRB06G11MPS -TSRA BKN007 SCT030CB", StationID: "URSS",
+			TAF        []TAF "xml:\"TAF\""
+			NumResults int   "xml:\"num_results,attr\""
+		}{TAF: []TAF{TAF{RawText: "TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040 BECMG 0708/0709 28006G11MPS SCT030CB TEMPO 0709/0717 -TSRA BECMG 0717/0718 05005MPS BKN011 TEMPO 0718/0806 VRB06G11MPS -TSRA BKN007 SCT030CB", StationID: "URSS",
 			IssueTime:     time.Date(2019, 06, 7, 4, 56, 0, 0, time.UTC),
 			BulletinTime:  time.Date(2019, 06, 7, 5, 0, 0, 0, time.UTC),
 			ValidTimeFrom: time.Date(2019, 06, 7, 6, 0, 0, 0, time.UTC),
-			ValidTimeTo:   time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC), Remarks: "", Latitude: 43.45, Longitude: 39.95, ElevationM: 16, Forecast: []struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{FcstTimeFrom: time.Date(2019, 6, 7, 6, 0, 0, 0, time.UTC),
+			ValidTimeTo:   time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC), Remarks: "", Latitude: 43.45, Longitude: 39.95, ElevationM: 16, Forecast: []Forecast{Forecast{FcstTimeFrom: time.Date(2019, 6, 7, 6, 0, 0, 0, time.UTC),
 				FcstTimeTo:      time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC),
-				ChangeIndicator: "", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 230, WindSpeedKt: 10, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{SkyCover: "FEW", CloudBaseFtAgl: 4000, CloudType: ""}}, TurbulenceCondition: []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				}(nil), IcingCondition: []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				}(nil), Temperature: []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				}(nil)}, struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{FcstTimeFrom: time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC),
+				ChangeIndicator: "", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 230, WindSpeedKt: 10, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []SkyCondition{SkyCondition{SkyCover: "FEW", CloudBaseFtAgl: 4000, CloudType: ""}}, TurbulenceCondition: []TurbulenceCondition(nil), IcingCondition: []IcingCondition(nil), Temperature: []Temperature(nil)}, Forecast{FcstTimeFrom: time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC),
 				FcstTimeTo:      time.Date(2019, 06, 7, 17, 0, 0, 0, time.UTC),
 				ChangeIndicator: "BECMG",
 				TimeBecoming:    time.Date(2019, 06, 7, 9, 0, 0, 0, time.UTC),
-				Probability:     "", WindDirDegrees: 280, WindSpeedKt: 12, WindGustKt: 21, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}, TurbulenceCondition: []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				}(nil), IcingCondition: []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				}(nil), Temperature: []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				}(nil)}, struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{FcstTimeFrom: time.Date(2019, 06, 7, 9, 0, 0, 0, time.UTC),
+				Probability:     "", WindDirDegrees: 280, WindSpeedKt: 12, WindGustKt: 21, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []SkyCondition{SkyCondition{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}, TurbulenceCondition: []TurbulenceCondition(nil), IcingCondition: []IcingCondition(nil), Temperature: []Temperature(nil)}, Forecast{FcstTimeFrom: time.Date(2019, 06, 7, 9, 0, 0, 0, time.UTC),
 				FcstTimeTo:      time.Date(2019, 06, 7, 17, 0, 0, 0, time.UTC),
-				ChangeIndicator: "TEMPO", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 0, WindSpeedKt: 0, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 0, AltimInHg: 0, VertVisFt: 0, WxString: "-TSRA", NotDecoded: "", SkyCondition: []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}(nil), TurbulenceCondition: []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				}(nil), IcingCondition: []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				}(nil), Temperature: []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				}(nil)}, struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{FcstTimeFrom: time.Date(2019, 06, 7, 17, 0, 0, 0, time.UTC),
+				ChangeIndicator: "TEMPO", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 0, WindSpeedKt: 0, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 0, AltimInHg: 0, VertVisFt: 0, WxString: "-TSRA", NotDecoded: "", SkyCondition: []SkyCondition(nil), TurbulenceCondition: []TurbulenceCondition(nil), IcingCondition: []IcingCondition(nil), Temperature: []Temperature(nil)}, Forecast{FcstTimeFrom: time.Date(2019, 06, 7, 17, 0, 0, 0, time.UTC),
 				FcstTimeTo:      time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC),
 				ChangeIndicator: "BECMG",
 				TimeBecoming:    time.Date(2019, 06, 7, 18, 0, 0, 0, time.UTC),
-				Probability:     "", WindDirDegrees: 50, WindSpeedKt: 10, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{SkyCover: "BKN", CloudBaseFtAgl: 1100, CloudType: ""}}, TurbulenceCondition: []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				}(nil), IcingCondition: []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				}(nil), Temperature: []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				}(nil)}, struct {
-				FcstTimeFrom        time.Time "xml:\"fcst_time_from\""
-				FcstTimeTo          time.Time "xml:\"fcst_time_to\""
-				ChangeIndicator     string    "xml:\"change_indicator\""
-				TimeBecoming        time.Time "xml:\"time_becoming\""
-				Probability         string    "xml:\"probability\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				WindShearHgtFtAgl   int       "xml:\"wind_shear_hgt_ft_agl\""
-				WindShearDirDegrees int       "xml:\"wind_shear_dir_degrees\""
-				WindShearSpeedKt    int       "xml:\"wind_shear_speed_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				VertVisFt           int       "xml:\"vert_vis_ft\""
-				WxString            string    "xml:\"wx_string\""
-				NotDecoded          string    "xml:\"not_decoded\""
-				SkyCondition        []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				} "xml:\"sky_condition\""
-				TurbulenceCondition []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				} "xml:\"turbulence_condition\""
-				IcingCondition []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				} "xml:\"icing_condition\""
-				Temperature []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				} "xml:\"temperature,omitempty\""
-			}{
+				Probability:     "", WindDirDegrees: 50, WindSpeedKt: 10, WindGustKt: 0, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 0, VertVisFt: 0, WxString: "", NotDecoded: "", SkyCondition: []SkyCondition{SkyCondition{SkyCover: "BKN", CloudBaseFtAgl: 1100, CloudType: ""}}, TurbulenceCondition: []TurbulenceCondition(nil), IcingCondition: []IcingCondition(nil), Temperature: []Temperature(nil)}, Forecast{
 				FcstTimeFrom:    time.Date(2019, 06, 7, 18, 0, 0, 0, time.UTC),
 				FcstTimeTo:      time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC),
-				ChangeIndicator: "TEMPO", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 0, WindSpeedKt: 12, WindGustKt: 21, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 0, AltimInHg: 0, VertVisFt: 0, WxString: "-TSRA", NotDecoded: "", SkyCondition: []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{SkyCover: "BKN", CloudBaseFtAgl: 700, CloudType: ""}, struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-					CloudType      string "xml:\"cloud_type,attr\""
-				}{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}, TurbulenceCondition: []struct {
-					TurbulenceIntensity   string "xml:\"turbulence_intensity,attr\""
-					TurbulenceMinAltFtAgl int    "xml:\"turbulence_min_alt_ft_agl,attr\""
-					TurbulenceMaxAltFtAgl int    "xml:\"turbulence_max_alt_ft_agl,attr\""
-				}(nil), IcingCondition: []struct {
-					IcingIntensity   string "xml:\"icing_intensity,attr\""
-					IcingMinAltFtAgl int    "xml:\"icing_min_alt_ft_agl,attr\""
-					IcingMaxAltFtAgl int    "xml:\"icing_max_alt_ft_agl,attr\""
-				}(nil), Temperature: []struct {
-					ValidTime time.Time "xml:\"valid_time,omitempty\""
-					SfcTempC  float32   "xml:\"sfc_temp_c,omitempty\""
-					MaxTempC  string    "xml:\"max_temp_c,omitempty\""
-					MinTempC  string    "xml:\"min_temp_c,omitempty\""
-				}(nil)}}}}, NumResults: 1}}
+				ChangeIndicator: "TEMPO", TimeBecoming: time.Time{}, Probability: "", WindDirDegrees: 0, WindSpeedKt: 12, WindGustKt: 21, WindShearHgtFtAgl: 0, WindShearDirDegrees: 0, WindShearSpeedKt: 0, VisibilityStatuteMi: 0, AltimInHg: 0, VertVisFt: 0, WxString: "-TSRA", NotDecoded: "", SkyCondition: []SkyCondition{SkyCondition{SkyCover: "BKN", CloudBaseFtAgl: 700, CloudType: ""}, SkyCondition{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}, TurbulenceCondition: []TurbulenceCondition(nil), IcingCondition: []IcingCondition(nil), Temperature: []Temperature(nil)}}}}, NumResults: 1}}
 
 		si, err := UnmarshalTafs(input)
 		Convey("struct should be builded correctly", func() {
@@ -608,156 +77,12 @@ func TestUnmarshalMetars(t *testing.T) {
 		}{Name: "metars"}, Request: struct {
 			Type string "xml:\"type,attr\""
 		}{Type: "retrieve"}, Errors: nil, Warnings: nil, TimeTakenMs: 4, Data: struct {
-			METAR []struct {
-				RawText             string    "xml:\"raw_text\""
-				StationID           string    "xml:\"station_id\""
-				ObservationTime     time.Time "xml:\"observation_time\""
-				Latitude            float32   "xml:\"latitude\""
-				Longitude           float32   "xml:\"longitude\""
-				TempC               float32   "xml:\"temp_c\""
-				DewpointC           float32   "xml:\"dewpoint_c\""
-				WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-				WindSpeedKt         int       "xml:\"wind_speed_kt\""
-				WindGustKt          int       "xml:\"wind_gust_kt\""
-				VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-				AltimInHg           float32   "xml:\"altim_in_hg\""
-				SeaLevelPressureMb  float32   "xml:\"sea_level_pressure_mb\""
-				QualityControlFlags struct {
-					Corrected               bool "xml:\"corrected\""
-					Auto                    bool "xml:\"auto\""
-					AutoStation             bool "xml:\"auto_station\""
-					MaintenanceIndicatorOn  bool "xml:\"maintenance_indicator_on\""
-					NoSignal                bool "xml:\"no_signal\""
-					LightningSensorOff      bool "xml:\"lightning_sensor_off\""
-					FreezingRainSensorOff   bool "xml:\"freezing_rain_sensor_off\""
-					PresentWeatherSensorOff bool "xml:\"present_weather_sensor_off\""
-				} "xml:\"quality_control_flags\""
-				WxString     string "xml:\"wx_string\""
-				SkyCondition []struct {
-					SkyCover       string "xml:\"sky_cover,attr\""
-					CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-				} "xml:\"sky_condition\""
-				FlightCategory            string  "xml:\"flight_category\""
-				ThreeHrPressureTendencyMb float32 "xml:\"three_hr_pressure_tendency_mb\""
-				MaxTC                     float32 "xml:\"maxT_c\""
-				MinTC                     float32 "xml:\"minT_c\""
-				MaxT24HrC                 float32 "xml:\"maxT24hr_c\""
-				MinT24HrC                 float32 "xml:\"minT24hr_c\""
-				PrecipIn                  float32 "xml:\"precip_in\""
-				Pcp3HrIn                  float32 "xml:\"pcp3hr_in\""
-				Pcp6HrIn                  float32 "xml:\"pcp6hr_in\""
-				Pcp24HrIn                 float32 "xml:\"pcp24hr_in\""
-				SnowIn                    float32 "xml:\"snow_in\""
-				VertVisFt                 int     "xml:\"vert_vis_ft\""
-				MetarType                 string  "xml:\"metar_type\""
-				ElevationM                float32 "xml:\"elevation_m\""
-			} "xml:\"METAR\""
-			NumResults int "xml:\"num_results,attr\""
-		}{METAR: []struct {
-			RawText             string    "xml:\"raw_text\""
-			StationID           string    "xml:\"station_id\""
-			ObservationTime     time.Time "xml:\"observation_time\""
-			Latitude            float32   "xml:\"latitude\""
-			Longitude           float32   "xml:\"longitude\""
-			TempC               float32   "xml:\"temp_c\""
-			DewpointC           float32   "xml:\"dewpoint_c\""
-			WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-			WindSpeedKt         int       "xml:\"wind_speed_kt\""
-			WindGustKt          int       "xml:\"wind_gust_kt\""
-			VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-			AltimInHg           float32   "xml:\"altim_in_hg\""
-			SeaLevelPressureMb  float32   "xml:\"sea_level_pressure_mb\""
-			QualityControlFlags struct {
-				Corrected               bool "xml:\"corrected\""
-				Auto                    bool "xml:\"auto\""
-				AutoStation             bool "xml:\"auto_station\""
-				MaintenanceIndicatorOn  bool "xml:\"maintenance_indicator_on\""
-				NoSignal                bool "xml:\"no_signal\""
-				LightningSensorOff      bool "xml:\"lightning_sensor_off\""
-				FreezingRainSensorOff   bool "xml:\"freezing_rain_sensor_off\""
-				PresentWeatherSensorOff bool "xml:\"present_weather_sensor_off\""
-			} "xml:\"quality_control_flags\""
-			WxString     string "xml:\"wx_string\""
-			SkyCondition []struct {
-				SkyCover       string "xml:\"sky_cover,attr\""
-				CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-			} "xml:\"sky_condition\""
-			FlightCategory            string  "xml:\"flight_category\""
-			ThreeHrPressureTendencyMb float32 "xml:\"three_hr_pressure_tendency_mb\""
-			MaxTC                     float32 "xml:\"maxT_c\""
-			MinTC                     float32 "xml:\"minT_c\""
-			MaxT24HrC                 float32 "xml:\"maxT24hr_c\""
-			MinT24HrC                 float32 "xml:\"minT24hr_c\""
-			PrecipIn                  float32 "xml:\"precip_in\""
-			Pcp3HrIn                  float32 "xml:\"pcp3hr_in\""
-			Pcp6HrIn                  float32 "xml:\"pcp6hr_in\""
-			Pcp24HrIn                 float32 "xml:\"pcp24hr_in\""
-			SnowIn                    float32 "xml:\"snow_in\""
-			VertVisFt                 int     "xml:\"vert_vis_ft\""
-			MetarType                 string  "xml:\"metar_type\""
-			ElevationM                float32 "xml:\"elevation_m\""
-		}{struct {
-			RawText             string    "xml:\"raw_text\""
-			StationID           string    "xml:\"station_id\""
-			ObservationTime     time.Time "xml:\"observation_time\""
-			Latitude            float32   "xml:\"latitude\""
-			Longitude           float32   "xml:\"longitude\""
-			TempC               float32   "xml:\"temp_c\""
-			DewpointC           float32   "xml:\"dewpoint_c\""
-			WindDirDegrees      int       "xml:\"wind_dir_degrees\""
-			WindSpeedKt         int       "xml:\"wind_speed_kt\""
-			WindGustKt          int       "xml:\"wind_gust_kt\""
-			VisibilityStatuteMi float32   "xml:\"visibility_statute_mi\""
-			AltimInHg           float32   "xml:\"altim_in_hg\""
-			SeaLevelPressureMb  float32   "xml:\"sea_level_pressure_mb\""
-			QualityControlFlags struct {
-				Corrected               bool "xml:\"corrected\""
-				Auto                    bool "xml:\"auto\""
-				AutoStation             bool "xml:\"auto_station\""
-				MaintenanceIndicatorOn  bool "xml:\"maintenance_indicator_on\""
-				NoSignal                bool "xml:\"no_signal\""
-				LightningSensorOff      bool "xml:\"lightning_sensor_off\""
-				FreezingRainSensorOff   bool "xml:\"freezing_rain_sensor_off\""
-				PresentWeatherSensorOff bool "xml:\"present_weather_sensor_off\""
-			} "xml:\"quality_control_flags\""
-			WxString     string "xml:\"wx_string\""
-			SkyCondition []struct {
-				SkyCover       string "xml:\"sky_cover,attr\""
-				CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-			} "xml:\"sky_condition\""
-			FlightCategory            string  "xml:\"flight_category\""
-			ThreeHrPressureTendencyMb float32 "xml:\"three_hr_pressure_tendency_mb\""
-			MaxTC                     float32 "xml:\"maxT_c\""
-			MinTC                     float32 "xml:\"minT_c\""
-			MaxT24HrC                 float32 "xml:\"maxT24hr_c\""
-			MinT24HrC                 float32 "xml:\"minT24hr_c\""
-			PrecipIn                  float32 "xml:\"precip_in\""
-			Pcp3HrIn                  float32 "xml:\"pcp3hr_in\""
-			Pcp6HrIn                  float32 "xml:\"pcp6hr_in\""
-			Pcp24HrIn                 float32 "xml:\"pcp24hr_in\""
-			SnowIn                    float32 "xml:\"snow_in\""
-			VertVisFt                 int     "xml:\"vert_vis_ft\""
-			MetarType                 string  "xml:\"metar_type\""
-			ElevationM                float32 "xml:\"elevation_m\""
-		}{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG",
+			METAR      []METAR "xml:\"METAR\""
+			NumResults int     "xml:\"num_results,attr\""
+		}{METAR: []METAR{METAR{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG",
 			StationID:       "ULLI",
 			ObservationTime: time.Date(2019, 06, 10, 8, 0, 0, 0, time.UTC),
-			Latitude:        59.8, Longitude: 30.27, TempC: 20, DewpointC: 11, WindDirDegrees: 230, WindSpeedKt: 14, WindGustKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 30.177166, SeaLevelPressureMb: 0, QualityControlFlags: struct {
-				Corrected               bool "xml:\"corrected\""
-				Auto                    bool "xml:\"auto\""
-				AutoStation             bool "xml:\"auto_station\""
-				MaintenanceIndicatorOn  bool "xml:\"maintenance_indicator_on\""
-				NoSignal                bool "xml:\"no_signal\""
-				LightningSensorOff      bool "xml:\"lightning_sensor_off\""
-				FreezingRainSensorOff   bool "xml:\"freezing_rain_sensor_off\""
-				PresentWeatherSensorOff bool "xml:\"present_weather_sensor_off\""
-			}{Corrected: false, Auto: false, AutoStation: false, MaintenanceIndicatorOn: false, NoSignal: true, LightningSensorOff: false, FreezingRainSensorOff: false, PresentWeatherSensorOff: false}, WxString: "", SkyCondition: []struct {
-				SkyCover       string "xml:\"sky_cover,attr\""
-				CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-			}{struct {
-				SkyCover       string "xml:\"sky_cover,attr\""
-				CloudBaseFtAgl int    "xml:\"cloud_base_ft_agl,attr\""
-			}{SkyCover: "FEW", CloudBaseFtAgl: 4000}}, FlightCategory: "VFR", ThreeHrPressureTendencyMb: 0, MaxTC: 0, MinTC: 0, MaxT24HrC: 0, MinT24HrC: 0, PrecipIn: 0, Pcp3HrIn: 0, Pcp6HrIn: 0, Pcp24HrIn: 0, SnowIn: 0, VertVisFt: 0, MetarType: "METAR", ElevationM: 4}}, NumResults: 1}}
+			Latitude:        59.8, Longitude: 30.27, TempC: 20, DewpointC: 11, WindDirDegrees: 230, WindSpeedKt: 14, WindGustKt: 0, VisibilityStatuteMi: 6.21, AltimInHg: 30.177166, SeaLevelPressureMb: 0, QualityControlFlags: QualityControlFlags{Corrected: false, Auto: false, AutoStation: false, MaintenanceIndicatorOn: false, NoSignal: true, LightningSensorOff: false, FreezingRainSensorOff: false, PresentWeatherSensorOff: false}, WxString: "", SkyCondition: []SkyCondition{SkyCondition{SkyCover: "FEW", CloudBaseFtAgl: 4000}}, FlightCategory: "VFR", ThreeHrPressureTendencyMb: 0, MaxTC: 0, MinTC: 0, MaxT24HrC: 0, MinT24HrC: 0, PrecipIn: 0, Pcp3HrIn: 0, Pcp6HrIn: 0, Pcp24HrIn: 0, SnowIn: 0, VertVisFt: 0, MetarType: "METAR", ElevationM: 4}}, NumResults: 1}}
 
 		si, err := UnmarshalMetars(input)
 		Convey("struct should be builded correctly", func() {
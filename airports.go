@@ -0,0 +1,273 @@
+package addstogo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urkk/addstogo/geodesy"
+)
+
+// Airport is an airport of the OurAirports data set.
+type Airport struct {
+	Ident string
+	// Type is one of large_airport, medium_airport, small_airport, heliport, seaplane_base, balloonport or closed.
+	Type             string
+	Name             string
+	Latitude         float64
+	Longitude        float64
+	ElevationFt      int
+	Country          string
+	Region           string
+	Municipality     string
+	ScheduledService bool
+	ICAO             string
+	GPSCode          string
+	IATA             string
+	LocalCode        string
+	Runways          []AirportRunway
+}
+
+// AirportRunway is a runway with its two ends.
+type AirportRunway struct {
+	LengthFt int
+	WidthFt  int
+	Surface  string
+	Lighted  bool
+	Closed   bool
+	LE, HE   RunwayEnd
+}
+
+// RunwayEnd is the low or high numbered end of a runway. HeadingDegT is zero when unknown.
+type RunwayEnd struct {
+	Ident                string
+	Latitude, Longitude  float64
+	ElevationFt          int
+	HeadingDegT          float64
+	DisplacedThresholdFt int
+}
+
+// Position returns the airport reference point.
+func (a *Airport) Position() geodesy.Point {
+	return geodesy.Point{Latitude: a.Latitude, Longitude: a.Longitude}
+}
+
+// RunwayDirections returns both directions of every open runway for wind computations, with true headings.
+// Headings from the designators, used when the true heading is unknown, are converted with the declination at the time.
+func (a *Airport) RunwayDirections(at time.Time) []Runway {
+	declination := Declination(a, float64(a.ElevationFt)*0.3048, at)
+	var directions []Runway
+	for _, r := range a.Runways {
+		if r.Closed {
+			continue
+		}
+		for _, end := range []RunwayEnd{r.LE, r.HE} {
+			if end.Ident == "" {
+				continue
+			}
+			heading := end.HeadingDegT
+			if heading == 0 {
+				parsed, err := ParseRunways(end.Ident, declination)
+				if err != nil {
+					continue
+				}
+				heading = parsed[0].HeadingDeg
+			}
+			directions = append(directions, Runway{Ident: end.Ident, HeadingDeg: heading})
+		}
+	}
+	return directions
+}
+
+// Airports is an index of airports by the codes used as METAR and TAF station identifiers.
+type Airports struct {
+	list   []*Airport
+	byCode map[string]*Airport
+}
+
+// csvTable reads a CSV file with a header row.
+type csvTable struct {
+	r       *csv.Reader
+	columns map[string]int
+	record  []string
+}
+
+func newCSVTable(r io.Reader) (*csvTable, error) {
+	t := &csvTable{r: csv.NewReader(r), columns: make(map[string]int)}
+	t.r.ReuseRecord = true
+	header, err := t.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		t.columns[strings.TrimSpace(name)] = i
+	}
+	return t, nil
+}
+
+func (t *csvTable) next() (bool, error) {
+	record, err := t.r.Read()
+	if err == io.EOF {
+		return false, nil
+	}
+	t.record = record
+	return err == nil, err
+}
+
+func (t *csvTable) str(column string) string {
+	if i, ok := t.columns[column]; ok && i < len(t.record) {
+		return strings.TrimSpace(t.record[i])
+	}
+	return ""
+}
+
+func (t *csvTable) float(column string) float64 {
+	v, _ := strconv.ParseFloat(t.str(column), 64)
+	return v
+}
+
+func (t *csvTable) int(column string) int {
+	return int(t.float(column))
+}
+
+func (t *csvTable) bool(column string) bool {
+	switch strings.ToLower(t.str(column)) {
+	case "1", "yes", "true":
+		return true
+	}
+	return false
+}
+
+// LoadAirports reads the OurAirports airports.csv and, when not nil, runways.csv.
+func LoadAirports(airports, runways io.Reader) (*Airports, error) {
+	t, err := newCSVTable(airports)
+	if err != nil {
+		return nil, fmt.Errorf("addstogo: airports: %v", err)
+	}
+	x := &Airports{byCode: make(map[string]*Airport)}
+	byID := make(map[string]*Airport)
+	byIdent := make(map[string]*Airport)
+	for {
+		ok, err := t.next()
+		if err != nil {
+			return nil, fmt.Errorf("addstogo: airports: %v", err)
+		}
+		if !ok {
+			break
+		}
+		a := &Airport{
+			Ident:            t.str("ident"),
+			Type:             t.str("type"),
+			Name:             t.str("name"),
+			Latitude:         t.float("latitude_deg"),
+			Longitude:        t.float("longitude_deg"),
+			ElevationFt:      t.int("elevation_ft"),
+			Country:          t.str("iso_country"),
+			Region:           t.str("iso_region"),
+			Municipality:     t.str("municipality"),
+			ScheduledService: t.bool("scheduled_service"),
+			ICAO:             t.str("icao_code"),
+			GPSCode:          t.str("gps_code"),
+			IATA:             t.str("iata_code"),
+			LocalCode:        t.str("local_code"),
+		}
+		x.list = append(x.list, a)
+		byID[t.str("id")] = a
+		byIdent[a.Ident] = a
+	}
+	// an ICAO code wins over a GPS code, which wins over the OurAirports identifier
+	for _, code := range []func(a *Airport) string{
+		func(a *Airport) string { return a.Ident },
+		func(a *Airport) string { return a.GPSCode },
+		func(a *Airport) string { return a.ICAO },
+	} {
+		for _, a := range x.list {
+			if c := strings.ToUpper(code(a)); c != "" {
+				x.byCode[c] = a
+			}
+		}
+	}
+	if runways == nil {
+		return x, nil
+	}
+	if t, err = newCSVTable(runways); err != nil {
+		return nil, fmt.Errorf("addstogo: runways: %v", err)
+	}
+	for {
+		ok, err := t.next()
+		if err != nil {
+			return nil, fmt.Errorf("addstogo: runways: %v", err)
+		}
+		if !ok {
+			break
+		}
+		a, found := byID[t.str("airport_ref")]
+		if !found {
+			if a, found = byIdent[t.str("airport_ident")]; !found {
+				continue
+			}
+		}
+		end := func(prefix string) RunwayEnd {
+			return RunwayEnd{
+				Ident:                t.str(prefix + "ident"),
+				Latitude:             t.float(prefix + "latitude_deg"),
+				Longitude:            t.float(prefix + "longitude_deg"),
+				ElevationFt:          t.int(prefix + "elevation_ft"),
+				HeadingDegT:          t.float(prefix + "heading_degT"),
+				DisplacedThresholdFt: t.int(prefix + "displaced_threshold_ft"),
+			}
+		}
+		a.Runways = append(a.Runways, AirportRunway{
+			LengthFt: t.int("length_ft"),
+			WidthFt:  t.int("width_ft"),
+			Surface:  t.str("surface"),
+			Lighted:  t.bool("lighted"),
+			Closed:   t.bool("closed"),
+			LE:       end("le_"),
+			HE:       end("he_"),
+		})
+	}
+	for _, a := range x.list {
+		sort.SliceStable(a.Runways, func(i, j int) bool { return a.Runways[i].LengthFt > a.Runways[j].LengthFt })
+	}
+	return x, nil
+}
+
+// Len returns the number of airports.
+func (x *Airports) Len() int {
+	return len(x.list)
+}
+
+// Airport returns the airport of a METAR or TAF station identifier, matched against
+// the ICAO code, the GPS code and the OurAirports identifier.
+func (x *Airports) Airport(stationID string) (*Airport, bool) {
+	a, ok := x.byCode[strings.ToUpper(stationID)]
+	return a, ok
+}
+
+// IATA returns the airport with the IATA code.
+func (x *Airports) IATA(code string) (*Airport, bool) {
+	code = strings.ToUpper(code)
+	for _, a := range x.list {
+		if a.IATA == code {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Enrich links the stations to their airports and returns how many were found.
+func (x *Airports) Enrich(stations []Station) int {
+	found := 0
+	for i := range stations {
+		if a, ok := x.Airport(stations[i].StationID); ok {
+			stations[i].Airport = a
+			found++
+		}
+	}
+	return found
+}
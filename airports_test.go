@@ -0,0 +1,82 @@
+package addstogo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const airportsFixture = `"id","ident","type","name","latitude_deg","longitude_deg","elevation_ft","continent","iso_country","iso_region","municipality","scheduled_service","gps_code","iata_code","local_code","home_link","wikipedia_link","keywords"
+3875,"KSEA","large_airport","Seattle Tacoma International Airport",47.449001,-122.308998,433,"NA","US","US-WA","Seattle","yes","KSEA","SEA","SEA","https://www.portseattle.org/sea-tac","https://en.wikipedia.org/wiki/Seattle%E2%80%93Tacoma_International_Airport",
+3486,"KDEN","large_airport","Denver International Airport",39.861698150635,-104.672996521,5431,"NA","US","US-CO","Denver","yes","KDEN","DEN","DEN","http://www.flydenver.com/",,"DVX, KVDX"
+21180,"S50","small_airport","Auburn Municipal Airport",47.327702,-122.226997,63,"NA","US","US-WA","Auburn","no","KS50",,"S50",,,
+`
+
+const runwaysFixture = `"id","airport_ref","airport_ident","length_ft","width_ft","surface","lighted","closed","le_ident","le_latitude_deg","le_longitude_deg","le_elevation_ft","le_heading_degT","le_displaced_threshold_ft","he_ident","he_latitude_deg","he_longitude_deg","he_elevation_ft","he_heading_degT","he_displaced_threshold_ft"
+240000,3875,"KSEA",8500,150,"CON",1,0,"16C",47.4638,-122.311,429,180,,"34C",47.4405,-122.311,363,360,
+240001,3875,"KSEA",11901,150,"CON",1,0,"16L",47.4638,-122.308,432,180,,"34R",47.4311,-122.308,347,360,
+240002,3486,"KDEN",12000,150,"CON",1,0,"16L",,,,,,"34R",,,,,
+240003,3486,"KDEN",3000,75,"ASP",0,1,"09",,,,,,"27",,,,,
+`
+
+func TestAirports(t *testing.T) {
+	Convey("Airports loaded from OurAirports files", t, func() {
+		x, err := LoadAirports(strings.NewReader(airportsFixture), strings.NewReader(runwaysFixture))
+		So(err, ShouldBeNil)
+		So(x.Len(), ShouldEqual, 3)
+
+		Convey("should hold the airport details and runways, longest first", func() {
+			a, ok := x.Airport("ksea")
+			So(ok, ShouldBeTrue)
+			So(a.IATA, ShouldEqual, "SEA")
+			So(a.Municipality, ShouldEqual, "Seattle")
+			So(a.Type, ShouldEqual, "large_airport")
+			So(a.ScheduledService, ShouldBeTrue)
+			So(a.ElevationFt, ShouldEqual, 433)
+			So(len(a.Runways), ShouldEqual, 2)
+			So(a.Runways[0].LengthFt, ShouldEqual, 11901)
+			So(a.Runways[0].Lighted, ShouldBeTrue)
+			So(a.Runways[0].LE, ShouldResemble, RunwayEnd{Ident: "16L", Latitude: 47.4638, Longitude: -122.308, ElevationFt: 432, HeadingDegT: 180})
+		})
+		Convey("should give runway directions for wind computations", func() {
+			a, _ := x.Airport("KDEN")
+			at := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+			directions := a.RunwayDirections(at)
+			So(len(directions), ShouldEqual, 2)
+			So(directions[0].Ident, ShouldEqual, "16L")
+			So(directions[0].HeadingDeg, ShouldAlmostEqual, 160+Declination(a, 1655, at), 0.01)
+			So(directions[0].HeadingDeg, ShouldAlmostEqual, 167.95, 0.01)
+			So(directions[1].HeadingDeg, ShouldAlmostEqual, 347.95, 0.01)
+			a, _ = x.Airport("KSEA")
+			So(a.RunwayDirections(at)[0], ShouldResemble, Runway{"16L", 180})
+		})
+		Convey("should link station identifiers through the GPS code", func() {
+			a, ok := x.Airport("KS50")
+			So(ok, ShouldBeTrue)
+			So(a.Ident, ShouldEqual, "S50")
+			a, _ = x.IATA("den")
+			So(a.Ident, ShouldEqual, "KDEN")
+			_, ok = x.IATA("XXX")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("should enrich the stations", func() {
+			si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+			So(x.Enrich(si.Data.Station), ShouldEqual, 2)
+			c := NewCatalog(si.Data.Station)
+			s, _ := c.Station("KSEA")
+			So(s.Airport.Name, ShouldEqual, "Seattle Tacoma International Airport")
+		})
+	})
+	Convey("Airports without runways or with a broken file", t, func() {
+		x, err := LoadAirports(strings.NewReader(airportsFixture), nil)
+		So(err, ShouldBeNil)
+		a, _ := x.Airport("KSEA")
+		So(a.Runways, ShouldBeEmpty)
+		_, err = LoadAirports(strings.NewReader(""), nil)
+		So(err, ShouldNotBeNil)
+		_, err = LoadAirports(strings.NewReader("id,ident\n1,\"KSEA\n"), nil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,330 @@
+package addstogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urkk/addstogo/geodesy"
+)
+
+// Rule declares when an alert is raised. All the set conditions must hold.
+// A rule with ForecastWxContains is evaluated against TAFs, any other rule against METARs.
+type Rule struct {
+	Name string `json:"name"`
+	// Stations limits the rule to the listed stations. Empty means any station.
+	Stations []string `json:"stations,omitempty"`
+	// Near and RadiusNM limit the rule to reports within the radius of a station or a lat/lon point.
+	Near     string  `json:"near,omitempty"`
+	RadiusNM float64 `json:"radius_nm,omitempty"`
+
+	FlightCategoryBelow string  `json:"flight_category_below,omitempty"`
+	WindAboveKt         int     `json:"wind_above_kt,omitempty"`
+	GustAboveKt         int     `json:"gust_above_kt,omitempty"`
+	VisibilityBelowSM   float64 `json:"visibility_below_sm,omitempty"`
+	CeilingBelowFt      int     `json:"ceiling_below_ft,omitempty"`
+	WxContains          string  `json:"wx_contains,omitempty"`
+
+	// ForecastWxContains matches the forecast periods overlapping the next ForecastHours.
+	ForecastWxContains string  `json:"forecast_wx_contains,omitempty"`
+	ForecastHours      float64 `json:"forecast_hours,omitempty"`
+
+	// ClearMargin is how far past a numeric threshold a value must get back before the alert clears.
+	ClearMargin float64 `json:"clear_margin,omitempty"`
+	// ClearAfter is how many consecutive clear reports are needed before the alert clears. Zero means one.
+	ClearAfter int `json:"clear_after,omitempty"`
+}
+
+// LoadRules reads a JSON array of rules.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// AlertState is the transition reported by an alert.
+type AlertState string
+
+const (
+	// AlertRaised is sent when the rule starts to hold.
+	AlertRaised AlertState = "raised"
+	// AlertCleared is sent when the rule no longer holds.
+	AlertCleared AlertState = "cleared"
+)
+
+// Alert is raised or cleared by a rule on a report.
+type Alert struct {
+	Rule    string     `json:"rule"`
+	State   AlertState `json:"state"`
+	Station string     `json:"station"`
+	// Time is the observation time of the METAR or the issue time of the TAF.
+	Time    time.Time `json:"time"`
+	Reason  string    `json:"reason"`
+	RawText string    `json:"raw_text"`
+	METAR   *METAR    `json:"-"`
+	TAF     *TAF      `json:"-"`
+}
+
+// metarCondition tells whether the observation meets a condition, with thresholds relaxed by margin.
+type metarCondition func(m *METAR, margin float64) (bool, string)
+
+// tafCondition tells whether the forecast meets a condition in [from, to).
+type tafCondition func(t *TAF, from, to time.Time) (bool, string)
+
+type compiledRule struct {
+	Rule
+	stations map[string]bool
+	near     *Waypoint
+	metar    []metarCondition
+	taf      []tafCondition
+}
+
+type alertKey struct {
+	rule    int
+	station string
+}
+
+type alertState struct {
+	active bool
+	clear  int
+	last   time.Time
+}
+
+// AlertEngine evaluates rules against incoming reports and keeps the state of every rule and station.
+// It is safe for concurrent use.
+type AlertEngine struct {
+	rules []compiledRule
+	mu    sync.Mutex
+	state map[alertKey]*alertState
+}
+
+var flightCategoryRank = map[string]int{"LIFR": 0, "IFR": 1, "MVFR": 2, "VFR": 3}
+
+// NewAlertEngine validates the rules. The catalog resolves the Near stations and may be nil
+// when no rule uses one.
+func NewAlertEngine(rules []Rule, c *Catalog) (*AlertEngine, error) {
+	e := &AlertEngine{state: make(map[alertKey]*alertState)}
+	for _, r := range rules {
+		cr, err := compileRule(r, c)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, cr)
+	}
+	return e, nil
+}
+
+func compileRule(r Rule, c *Catalog) (compiledRule, error) {
+	cr := compiledRule{Rule: r}
+	if len(r.Stations) > 0 {
+		cr.stations = make(map[string]bool, len(r.Stations))
+		for _, s := range r.Stations {
+			cr.stations[strings.ToUpper(s)] = true
+		}
+	}
+	if r.Near != "" {
+		route, err := ParseRoute(r.Near, c)
+		if err != nil {
+			return cr, fmt.Errorf("addstogo: rule %q: %v", r.Name, err)
+		}
+		if len(route) != 1 || r.RadiusNM <= 0 {
+			return cr, fmt.Errorf("addstogo: rule %q: near needs one point and a radius", r.Name)
+		}
+		cr.near = &route[0]
+	}
+	if r.FlightCategoryBelow != "" {
+		limit, ok := flightCategoryRank[strings.ToUpper(r.FlightCategoryBelow)]
+		if !ok {
+			return cr, fmt.Errorf("addstogo: rule %q: unknown flight category %q", r.Name, r.FlightCategoryBelow)
+		}
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			rank, ok := flightCategoryRank[m.FlightCategory]
+			return ok && rank < limit, fmt.Sprintf("flight category %s below %s", m.FlightCategory, r.FlightCategoryBelow)
+		})
+	}
+	if r.WindAboveKt > 0 {
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			return float64(m.WindSpeedKt) > float64(r.WindAboveKt)-margin, fmt.Sprintf("wind %d kt above %d kt", m.WindSpeedKt, r.WindAboveKt)
+		})
+	}
+	if r.GustAboveKt > 0 {
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			return float64(m.WindGustKt) > float64(r.GustAboveKt)-margin, fmt.Sprintf("gusts %d kt above %d kt", m.WindGustKt, r.GustAboveKt)
+		})
+	}
+	if r.VisibilityBelowSM > 0 {
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			return groupsOf(m).visibility && float64(m.VisibilityStatuteMi) < r.VisibilityBelowSM+margin, fmt.Sprintf("visibility %g sm below %g sm", m.VisibilityStatuteMi, r.VisibilityBelowSM)
+		})
+	}
+	if r.CeilingBelowFt > 0 {
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			ceiling, ok := ceilingFt(m)
+			return ok && float64(ceiling) < float64(r.CeilingBelowFt)+margin, fmt.Sprintf("ceiling %d ft below %d ft", ceiling, r.CeilingBelowFt)
+		})
+	}
+	if r.WxContains != "" {
+		cr.metar = append(cr.metar, func(m *METAR, margin float64) (bool, string) {
+			return strings.Contains(m.WxString, r.WxContains), fmt.Sprintf("weather %s contains %s", m.WxString, r.WxContains)
+		})
+	}
+	if r.ForecastWxContains != "" {
+		if len(cr.metar) > 0 {
+			return cr, fmt.Errorf("addstogo: rule %q: forecast and observation conditions cannot be mixed", r.Name)
+		}
+		cr.taf = append(cr.taf, func(t *TAF, from, to time.Time) (bool, string) {
+			for _, f := range t.Forecast {
+				if f.FcstTimeFrom.Before(to) && f.FcstTimeTo.After(from) && strings.Contains(f.WxString, r.ForecastWxContains) {
+					return true, fmt.Sprintf("forecast %s from %s contains %s", f.WxString, f.FcstTimeFrom.UTC().Format("021504Z"), r.ForecastWxContains)
+				}
+			}
+			return false, fmt.Sprintf("no forecast of %s", r.ForecastWxContains)
+		})
+	}
+	if len(cr.metar) == 0 && len(cr.taf) == 0 {
+		return cr, fmt.Errorf("addstogo: rule %q has no condition", r.Name)
+	}
+	return cr, nil
+}
+
+// ceilingFt returns the height of the lowest broken or overcast layer or the vertical visibility.
+func ceilingFt(m *METAR) (int, bool) {
+	for _, sc := range m.SkyCondition {
+		switch sc.SkyCover {
+		case "BKN", "OVC":
+			return sc.CloudBaseFtAgl, true
+		}
+	}
+	if m.VertVisFt > 0 {
+		return m.VertVisFt, true
+	}
+	return 0, false
+}
+
+// applies tells whether the rule covers the station at the position.
+func (r *compiledRule) applies(station string, p Positioned) bool {
+	if r.stations != nil && !r.stations[station] {
+		return false
+	}
+	return r.near == nil || geodesy.DistanceNM(p.Position(), r.near.Position()) <= r.RadiusNM
+}
+
+// EvaluateMETAR runs the observation rules and returns the alerts raised or cleared by the report.
+// Reports older than the last one seen for a rule and station are ignored.
+func (e *AlertEngine) EvaluateMETAR(m METAR) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var alerts []Alert
+	for i := range e.rules {
+		r := &e.rules[i]
+		if len(r.metar) == 0 || !r.applies(m.StationID, m) {
+			continue
+		}
+		state := e.stateOf(i, m.StationID)
+		if !m.ObservationTime.After(state.last) {
+			continue
+		}
+		state.last = m.ObservationTime
+		margin := 0.0
+		if state.active {
+			margin = r.ClearMargin
+		}
+		matched, reasons := true, make([]string, 0, len(r.metar))
+		for _, condition := range r.metar {
+			ok, reason := condition(&m, margin)
+			matched = matched && ok
+			reasons = append(reasons, reason)
+		}
+		if s, ok := state.transition(matched, r.ClearAfter); ok {
+			report := m
+			alerts = append(alerts, Alert{Rule: r.Name, State: s, Station: m.StationID, Time: m.ObservationTime,
+				Reason: strings.Join(reasons, ", "), RawText: m.RawText, METAR: &report})
+		}
+	}
+	return alerts
+}
+
+// EvaluateTAF runs the forecast rules on the periods of the forecast overlapping the next hours from at
+// and returns the alerts raised or cleared. A zero time means now.
+func (e *AlertEngine) EvaluateTAF(t TAF, at time.Time) []Alert {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var alerts []Alert
+	for i := range e.rules {
+		r := &e.rules[i]
+		if len(r.taf) == 0 || !r.applies(t.StationID, t) {
+			continue
+		}
+		state := e.stateOf(i, t.StationID)
+		if at.Before(state.last) {
+			continue
+		}
+		state.last = at
+		to := at.Add(time.Duration(r.ForecastHours * float64(time.Hour)))
+		if r.ForecastHours <= 0 {
+			to = t.ValidTimeTo
+		}
+		matched, reasons := true, make([]string, 0, len(r.taf))
+		for _, condition := range r.taf {
+			ok, reason := condition(&t, at, to)
+			matched = matched && ok
+			reasons = append(reasons, reason)
+		}
+		if s, ok := state.transition(matched, r.ClearAfter); ok {
+			report := t
+			alerts = append(alerts, Alert{Rule: r.Name, State: s, Station: t.StationID, Time: t.IssueTime,
+				Reason: strings.Join(reasons, ", "), RawText: t.RawText, TAF: &report})
+		}
+	}
+	return alerts
+}
+
+func (e *AlertEngine) stateOf(rule int, station string) *alertState {
+	k := alertKey{rule, station}
+	s, ok := e.state[k]
+	if !ok {
+		s = &alertState{}
+		e.state[k] = s
+	}
+	return s
+}
+
+// transition updates the state with the outcome of a report and returns the change, if any.
+func (s *alertState) transition(matched bool, clearAfter int) (AlertState, bool) {
+	switch {
+	case matched:
+		s.clear = 0
+		if !s.active {
+			s.active = true
+			return AlertRaised, true
+		}
+	case s.active:
+		s.clear++
+		if s.clear >= clearAfter {
+			s.active, s.clear = false, 0
+			return AlertCleared, true
+		}
+	}
+	return "", false
+}
+
+// Active returns the names of the rules currently raised for the station.
+func (e *AlertEngine) Active(station string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var names []string
+	for i := range e.rules {
+		if s, ok := e.state[alertKey{i, station}]; ok && s.active {
+			names = append(names, e.rules[i].Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,105 @@
+package addstogo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const rulesFixture = `[
+	{"name": "ksea-ifr", "stations": ["ksea"], "flight_category_below": "MVFR", "clear_after": 2},
+	{"name": "gusts", "gust_above_kt": 30, "clear_margin": 5},
+	{"name": "ts-near-kden", "near": "KDEN", "radius_nm": 50, "wx_contains": "TS"},
+	{"name": "fzra-6h", "forecast_wx_contains": "FZRA", "forecast_hours": 6}
+]`
+
+func TestAlertEngine(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	metar := func(minutes int, m METAR) METAR {
+		m.ObservationTime = t0.Add(time.Duration(minutes) * time.Minute)
+		return m
+	}
+	Convey("Alert engine with rules from a file", t, func() {
+		rules, err := LoadRules(strings.NewReader(rulesFixture))
+		So(err, ShouldBeNil)
+		So(len(rules), ShouldEqual, 4)
+		si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+		e, err := NewAlertEngine(rules, NewCatalog(si.Data.Station))
+		So(err, ShouldBeNil)
+
+		Convey("should raise once and clear after consecutive clear reports", func() {
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "KSEA", FlightCategory: "VFR"})), ShouldBeEmpty)
+			alerts := e.EvaluateMETAR(metar(60, METAR{StationID: "KSEA", FlightCategory: "IFR", RawText: "KSEA 071000Z 00000KT 1SM BR OVC004"}))
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Rule, ShouldEqual, "ksea-ifr")
+			So(alerts[0].State, ShouldEqual, AlertRaised)
+			So(alerts[0].RawText, ShouldEqual, "KSEA 071000Z 00000KT 1SM BR OVC004")
+			So(alerts[0].Reason, ShouldEqual, "flight category IFR below MVFR")
+			So(e.Active("KSEA"), ShouldResemble, []string{"ksea-ifr"})
+			So(e.EvaluateMETAR(metar(90, METAR{StationID: "KSEA", FlightCategory: "LIFR"})), ShouldBeEmpty)
+			So(e.EvaluateMETAR(metar(120, METAR{StationID: "KSEA", FlightCategory: "MVFR"})), ShouldBeEmpty)
+			alerts = e.EvaluateMETAR(metar(180, METAR{StationID: "KSEA", FlightCategory: "VFR"}))
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].State, ShouldEqual, AlertCleared)
+			So(e.Active("KSEA"), ShouldBeEmpty)
+		})
+		Convey("should not flap around a numeric threshold", func() {
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "URSS", WindGustKt: 35}))[0].State, ShouldEqual, AlertRaised)
+			So(e.EvaluateMETAR(metar(30, METAR{StationID: "URSS", WindGustKt: 28})), ShouldBeEmpty)
+			So(e.EvaluateMETAR(metar(60, METAR{StationID: "URSS", WindGustKt: 31})), ShouldBeEmpty)
+			So(e.EvaluateMETAR(metar(90, METAR{StationID: "URSS", WindGustKt: 25}))[0].State, ShouldEqual, AlertCleared)
+		})
+		Convey("should ignore stale reports", func() {
+			So(e.EvaluateMETAR(metar(60, METAR{StationID: "URSS", WindGustKt: 35})), ShouldHaveLength, 1)
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "URSS"})), ShouldBeEmpty)
+			So(e.EvaluateMETAR(metar(60, METAR{StationID: "URSS"})), ShouldBeEmpty)
+		})
+		Convey("should limit rules to the radius", func() {
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "KAPA", Latitude: 39.57, Longitude: -104.85, WxString: "+TSRA"}))[0].Rule, ShouldEqual, "ts-near-kden")
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "KSEA", Latitude: 47.45, Longitude: -122.32, FlightCategory: "VFR", WxString: "TS"})), ShouldBeEmpty)
+		})
+		Convey("should look ahead in the TAF timeline", func() {
+			taf := TAF{StationID: "KDEN", IssueTime: t0, ValidTimeFrom: t0, ValidTimeTo: t0.Add(24 * time.Hour), RawText: "TAF KDEN ...", Forecast: []Forecast{
+				{FcstTimeFrom: t0, FcstTimeTo: t0.Add(8 * time.Hour), WxString: "-RA"},
+				{FcstTimeFrom: t0.Add(8 * time.Hour), FcstTimeTo: t0.Add(24 * time.Hour), WxString: "FZRA"},
+			}}
+			So(e.EvaluateTAF(taf, t0), ShouldBeEmpty)
+			alerts := e.EvaluateTAF(taf, t0.Add(3*time.Hour))
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Rule, ShouldEqual, "fzra-6h")
+			So(alerts[0].TAF.RawText, ShouldEqual, "TAF KDEN ...")
+			So(alerts[0].Reason, ShouldEqual, "forecast FZRA from 071700Z contains FZRA")
+		})
+	})
+	Convey("Ceiling and visibility rules", t, func() {
+		e, err := NewAlertEngine([]Rule{{Name: "low", CeilingBelowFt: 200}, {Name: "fog", VisibilityBelowSM: 1}}, nil)
+		So(err, ShouldBeNil)
+
+		Convey("should take an obscured sky from the vertical visibility", func() {
+			alerts := e.EvaluateMETAR(metar(0, METAR{StationID: "KSEA", VisibilityStatuteMi: 2, VertVisFt: 100,
+				SkyCondition: []SkyCondition{{SkyCover: "OVX"}}, RawText: "KSEA 070900Z 00000KT 2SM BR VV001"}))
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Reason, ShouldEqual, "ceiling 100 ft below 200 ft")
+			So(e.EvaluateMETAR(metar(30, METAR{StationID: "KSEA", VisibilityStatuteMi: 2, VertVisFt: 300,
+				SkyCondition: []SkyCondition{{SkyCover: "OVX"}}, RawText: "KSEA 070930Z 00000KT 2SM BR VV003"})), ShouldHaveLength, 1)
+		})
+		Convey("should not fire on an unreported visibility", func() {
+			So(e.EvaluateMETAR(metar(0, METAR{StationID: "KSEA", RawText: "KSEA 070900Z 00000KT SKC"})), ShouldBeEmpty)
+			So(e.EvaluateMETAR(metar(30, METAR{StationID: "KSEA", VisibilityStatuteMi: 0.5, RawText: "KSEA 070930Z 00000KT 1/2SM FG SKC"}))[0].Rule, ShouldEqual, "fog")
+		})
+	})
+	Convey("Invalid rules should be rejected", t, func() {
+		_, err := NewAlertEngine([]Rule{{Name: "empty"}}, nil)
+		So(err, ShouldNotBeNil)
+		_, err = NewAlertEngine([]Rule{{Name: "bad", FlightCategoryBelow: "XFR"}}, nil)
+		So(err, ShouldNotBeNil)
+		_, err = NewAlertEngine([]Rule{{Name: "mixed", GustAboveKt: 30, ForecastWxContains: "TS"}}, nil)
+		So(err, ShouldNotBeNil)
+		_, err = NewAlertEngine([]Rule{{Name: "near", Near: "KDEN", RadiusNM: 10, WxContains: "TS"}}, nil)
+		So(err, ShouldNotBeNil)
+		_, err = LoadRules(strings.NewReader("{"))
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,570 @@
+package addstogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inHgPerHPa is the factor used by the legacy dataserver to convert QNH to inches of mercury.
+const inHgPerHPa = 0.02952756
+
+// apiNumber accepts the numbers, numeric strings ("10+", "VRB") and nulls
+// that aviationweather.gov/api/data mixes within the same field.
+type apiNumber struct {
+	Value float64
+	Valid bool
+}
+
+func (n *apiNumber) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*n = apiNumber{}
+		return nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		s = strings.TrimRight(strings.TrimSpace(s), "+")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		// non numeric values like "VRB" are reported as zero, as in the XML feed
+		*n = apiNumber{}
+		return nil
+	}
+	*n = apiNumber{Value: v, Valid: true}
+	return nil
+}
+
+func (n apiNumber) float32() float32 {
+	return float32(n.Value)
+}
+
+func (n apiNumber) int() int {
+	if n.Value < 0 {
+		return int(n.Value - 0.5)
+	}
+	return int(n.Value + 0.5)
+}
+
+// apiTime accepts unix timestamps as well as the textual forms used by the API.
+type apiTime struct {
+	time.Time
+}
+
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+func (t *apiTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+	if !bytes.HasPrefix(data, []byte(`"`)) {
+		sec, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("addstogo: invalid time %s", data)
+		}
+		t.Time = time.Unix(int64(sec), 0).UTC()
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	for _, layout := range apiTimeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("addstogo: invalid time %q", s)
+}
+
+type apiCloud struct {
+	Cover string    `json:"cover"`
+	Base  apiNumber `json:"base"`
+	Type  string    `json:"type"`
+}
+
+func (c apiCloud) skyCondition() SkyCondition {
+	return SkyCondition{SkyCover: c.Cover, CloudBaseFtAgl: c.Base.int(), CloudType: c.Type}
+}
+
+type apiMetar struct {
+	IcaoID     string     `json:"icaoId"`
+	ObsTime    apiTime    `json:"obsTime"`
+	ReportTime apiTime    `json:"reportTime"`
+	Temp       apiNumber  `json:"temp"`
+	Dewp       apiNumber  `json:"dewp"`
+	Wdir       apiNumber  `json:"wdir"`
+	Wspd       apiNumber  `json:"wspd"`
+	Wgst       apiNumber  `json:"wgst"`
+	Visib      apiNumber  `json:"visib"`
+	Altim      apiNumber  `json:"altim"`
+	Slp        apiNumber  `json:"slp"`
+	QcField    int        `json:"qcField"`
+	WxString   string     `json:"wxString"`
+	PresTend   apiNumber  `json:"presTend"`
+	MaxT       apiNumber  `json:"maxT"`
+	MinT       apiNumber  `json:"minT"`
+	MaxT24     apiNumber  `json:"maxT24"`
+	MinT24     apiNumber  `json:"minT24"`
+	Precip     apiNumber  `json:"precip"`
+	Pcp3hr     apiNumber  `json:"pcp3hr"`
+	Pcp6hr     apiNumber  `json:"pcp6hr"`
+	Pcp24hr    apiNumber  `json:"pcp24hr"`
+	Snow       apiNumber  `json:"snow"`
+	VertVis    apiNumber  `json:"vertVis"`
+	MetarType  string     `json:"metarType"`
+	RawOb      string     `json:"rawOb"`
+	Lat        apiNumber  `json:"lat"`
+	Lon        apiNumber  `json:"lon"`
+	Elev       apiNumber  `json:"elev"`
+	FltCat     string     `json:"fltCat"`
+	Clouds     []apiCloud `json:"clouds"`
+}
+
+// qcField bits, in the order of the legacy quality_control_flags elements.
+const (
+	qcCorrected = 1 << iota
+	qcAuto
+	qcAutoStation
+	qcMaintenanceIndicatorOn
+	qcNoSignal
+	qcLightningSensorOff
+	qcFreezingRainSensorOff
+	qcPresentWeatherSensorOff
+)
+
+func (m apiMetar) metar() METAR {
+	result := METAR{
+		RawText:                   m.RawOb,
+		StationID:                 m.IcaoID,
+		ObservationTime:           m.ObsTime.Time,
+		Latitude:                  m.Lat.float32(),
+		Longitude:                 m.Lon.float32(),
+		TempC:                     m.Temp.float32(),
+		DewpointC:                 m.Dewp.float32(),
+		WindDirDegrees:            m.Wdir.int(),
+		WindSpeedKt:               m.Wspd.int(),
+		WindGustKt:                m.Wgst.int(),
+		VisibilityStatuteMi:       m.Visib.float32(),
+		AltimInHg:                 float32(m.Altim.Value * inHgPerHPa),
+		SeaLevelPressureMb:        m.Slp.float32(),
+		WxString:                  m.WxString,
+		FlightCategory:            m.FltCat,
+		ThreeHrPressureTendencyMb: m.PresTend.float32(),
+		MaxTC:                     m.MaxT.float32(),
+		MinTC:                     m.MinT.float32(),
+		MaxT24HrC:                 m.MaxT24.float32(),
+		MinT24HrC:                 m.MinT24.float32(),
+		PrecipIn:                  m.Precip.float32(),
+		Pcp3HrIn:                  m.Pcp3hr.float32(),
+		Pcp6HrIn:                  m.Pcp6hr.float32(),
+		Pcp24HrIn:                 m.Pcp24hr.float32(),
+		SnowIn:                    m.Snow.float32(),
+		VertVisFt:                 m.VertVis.int(),
+		MetarType:                 m.MetarType,
+		ElevationM:                m.Elev.float32(),
+		QualityControlFlags: QualityControlFlags{
+			Corrected:               m.QcField&qcCorrected != 0,
+			Auto:                    m.QcField&qcAuto != 0,
+			AutoStation:             m.QcField&qcAutoStation != 0,
+			MaintenanceIndicatorOn:  m.QcField&qcMaintenanceIndicatorOn != 0,
+			NoSignal:                m.QcField&qcNoSignal != 0,
+			LightningSensorOff:      m.QcField&qcLightningSensorOff != 0,
+			FreezingRainSensorOff:   m.QcField&qcFreezingRainSensorOff != 0,
+			PresentWeatherSensorOff: m.QcField&qcPresentWeatherSensorOff != 0,
+		},
+	}
+	if result.ObservationTime.IsZero() {
+		result.ObservationTime = m.ReportTime.Time
+	}
+	for _, c := range m.Clouds {
+		result.SkyCondition = append(result.SkyCondition, c.skyCondition())
+	}
+	return result
+}
+
+type apiIcgTurb struct {
+	Var       string    `json:"var"`
+	Intensity apiNumber `json:"intensity"`
+	MinAlt    apiNumber `json:"minAlt"`
+	MaxAlt    apiNumber `json:"maxAlt"`
+}
+
+type apiTemp struct {
+	ValidTime apiTime   `json:"validTime"`
+	SfcTemp   apiNumber `json:"sfcTemp"`
+	MaxOrMin  string    `json:"maxOrMin"`
+}
+
+type apiForecast struct {
+	TimeFrom    apiTime      `json:"timeFrom"`
+	TimeTo      apiTime      `json:"timeTo"`
+	TimeBec     apiTime      `json:"timeBec"`
+	FcstChange  string       `json:"fcstChange"`
+	Probability apiNumber    `json:"probability"`
+	Wdir        apiNumber    `json:"wdir"`
+	Wspd        apiNumber    `json:"wspd"`
+	Wgst        apiNumber    `json:"wgst"`
+	WshearHgt   apiNumber    `json:"wshearHgt"`
+	WshearDir   apiNumber    `json:"wshearDir"`
+	WshearSpd   apiNumber    `json:"wshearSpd"`
+	Visib       apiNumber    `json:"visib"`
+	Altim       apiNumber    `json:"altim"`
+	VertVis     apiNumber    `json:"vertVis"`
+	WxString    string       `json:"wxString"`
+	NotDecoded  string       `json:"notDecoded"`
+	Clouds      []apiCloud   `json:"clouds"`
+	IcgTurb     []apiIcgTurb `json:"icgTurb"`
+	Temp        []apiTemp    `json:"temp"`
+}
+
+func (f apiForecast) forecast() Forecast {
+	result := Forecast{
+		FcstTimeFrom:        f.TimeFrom.Time,
+		FcstTimeTo:          f.TimeTo.Time,
+		ChangeIndicator:     f.FcstChange,
+		TimeBecoming:        f.TimeBec.Time,
+		WindDirDegrees:      f.Wdir.int(),
+		WindSpeedKt:         f.Wspd.int(),
+		WindGustKt:          f.Wgst.int(),
+		WindShearHgtFtAgl:   f.WshearHgt.int(),
+		WindShearDirDegrees: f.WshearDir.int(),
+		WindShearSpeedKt:    f.WshearSpd.int(),
+		VisibilityStatuteMi: f.Visib.float32(),
+		AltimInHg:           float32(f.Altim.Value * inHgPerHPa),
+		VertVisFt:           f.VertVis.int(),
+		WxString:            f.WxString,
+		NotDecoded:          f.NotDecoded,
+	}
+	if f.Probability.Valid {
+		result.Probability = strconv.Itoa(f.Probability.int())
+	}
+	for _, c := range f.Clouds {
+		result.SkyCondition = append(result.SkyCondition, c.skyCondition())
+	}
+	for _, c := range f.IcgTurb {
+		intensity := strconv.Itoa(c.Intensity.int())
+		switch c.Var {
+		case "ICE":
+			result.IcingCondition = append(result.IcingCondition, IcingCondition{
+				IcingIntensity: intensity, IcingMinAltFtAgl: c.MinAlt.int(), IcingMaxAltFtAgl: c.MaxAlt.int()})
+		case "TURB":
+			result.TurbulenceCondition = append(result.TurbulenceCondition, TurbulenceCondition{
+				TurbulenceIntensity: intensity, TurbulenceMinAltFtAgl: c.MinAlt.int(), TurbulenceMaxAltFtAgl: c.MaxAlt.int()})
+		}
+	}
+	for _, t := range f.Temp {
+		temp := Temperature{ValidTime: t.ValidTime.Time}
+		switch strings.ToUpper(t.MaxOrMin) {
+		case "MAX":
+			temp.MaxTempC = strconv.FormatFloat(t.SfcTemp.Value, 'f', -1, 32)
+		case "MIN":
+			temp.MinTempC = strconv.FormatFloat(t.SfcTemp.Value, 'f', -1, 32)
+		default:
+			temp.SfcTempC = t.SfcTemp.float32()
+		}
+		result.Temperature = append(result.Temperature, temp)
+	}
+	return result
+}
+
+type apiTaf struct {
+	IcaoID        string        `json:"icaoId"`
+	BulletinTime  apiTime       `json:"bulletinTime"`
+	IssueTime     apiTime       `json:"issueTime"`
+	ValidTimeFrom apiTime       `json:"validTimeFrom"`
+	ValidTimeTo   apiTime       `json:"validTimeTo"`
+	RawTAF        string        `json:"rawTAF"`
+	Remarks       string        `json:"remarks"`
+	Lat           apiNumber     `json:"lat"`
+	Lon           apiNumber     `json:"lon"`
+	Elev          apiNumber     `json:"elev"`
+	Fcsts         []apiForecast `json:"fcsts"`
+}
+
+func (t apiTaf) taf() TAF {
+	result := TAF{
+		RawText:       t.RawTAF,
+		StationID:     t.IcaoID,
+		IssueTime:     t.IssueTime.Time,
+		BulletinTime:  t.BulletinTime.Time,
+		ValidTimeFrom: t.ValidTimeFrom.Time,
+		ValidTimeTo:   t.ValidTimeTo.Time,
+		Remarks:       t.Remarks,
+		Latitude:      t.Lat.float32(),
+		Longitude:     t.Lon.float32(),
+		ElevationM:    t.Elev.float32(),
+	}
+	for _, f := range t.Fcsts {
+		result.Forecast = append(result.Forecast, f.forecast())
+	}
+	return result
+}
+
+type apiStation struct {
+	IcaoID   string          `json:"icaoId"`
+	ID       string          `json:"id"`
+	WmoID    string          `json:"wmoId"`
+	Site     string          `json:"site"`
+	Lat      apiNumber       `json:"lat"`
+	Lon      apiNumber       `json:"lon"`
+	Elev     apiNumber       `json:"elev"`
+	State    string          `json:"state"`
+	Country  string          `json:"country"`
+	SiteType json.RawMessage `json:"siteType"`
+}
+
+func (s apiStation) station() Station {
+	result := Station{
+		StationID:  s.IcaoID,
+		WMOID:      s.WmoID,
+		Latitude:   s.Lat.float32(),
+		Longitude:  s.Lon.float32(),
+		ElevationM: s.Elev.float32(),
+		Site:       s.Site,
+		State:      s.State,
+		Country:    s.Country,
+	}
+	if result.StationID == "" {
+		result.StationID = s.ID
+	}
+	// siteType is either a list of products or a single comma separated string
+	var types []string
+	if err := json.Unmarshal(s.SiteType, &types); err != nil {
+		var list string
+		if json.Unmarshal(s.SiteType, &list) == nil {
+			types = strings.Split(list, ",")
+		}
+	}
+	for _, t := range types {
+		switch strings.TrimSpace(t) {
+		case "METAR":
+			result.SiteType.METAR = true
+		case "TAF":
+			result.SiteType.TAF = true
+		case "NEXRAD":
+			result.SiteType.NEXRAD = true
+		case "WFO_office", "WFO":
+			result.SiteType.WFOoffice = true
+		case "rawinsonde", "RAOB":
+			result.SiteType.Rawinsonde = true
+		case "wind_profiler", "PROF":
+			result.SiteType.WindProfiler = true
+		}
+	}
+	return result
+}
+
+// geoJSONCollection is a GeoJSON FeatureCollection of points.
+type geoJSONCollection struct {
+	Features []struct {
+		Properties json.RawMessage `json:"properties"`
+		Geometry   struct {
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// apiMetarProperties are the METAR GeoJSON feature properties.
+type apiMetarProperties struct {
+	ID        string    `json:"id"`
+	ObsTime   apiTime   `json:"obsTime"`
+	Temp      apiNumber `json:"temp"`
+	Dewp      apiNumber `json:"dewp"`
+	Wspd      apiNumber `json:"wspd"`
+	Wdir      apiNumber `json:"wdir"`
+	Wgst      apiNumber `json:"wgst"`
+	Ceil      apiNumber `json:"ceil"`
+	Cover     string    `json:"cover"`
+	Visib     apiNumber `json:"visib"`
+	Fltcat    string    `json:"fltcat"`
+	Altim     apiNumber `json:"altim"`
+	Slp       apiNumber `json:"slp"`
+	WxString  string    `json:"wx"`
+	RawOb     string    `json:"rawOb"`
+	MetarType string    `json:"metarType"`
+	Elev      apiNumber `json:"elev"`
+}
+
+// apiTafProperties are the TAF GeoJSON feature properties, one TAF with its forecast periods.
+type apiTafProperties struct {
+	apiTaf
+	ID string `json:"id"`
+}
+
+func (p apiMetarProperties) metar() METAR {
+	result := METAR{
+		RawText:             p.RawOb,
+		StationID:           p.ID,
+		ObservationTime:     p.ObsTime.Time,
+		TempC:               p.Temp.float32(),
+		DewpointC:           p.Dewp.float32(),
+		WindDirDegrees:      p.Wdir.int(),
+		WindSpeedKt:         p.Wspd.int(),
+		WindGustKt:          p.Wgst.int(),
+		VisibilityStatuteMi: p.Visib.float32(),
+		AltimInHg:           float32(p.Altim.Value * inHgPerHPa),
+		SeaLevelPressureMb:  p.Slp.float32(),
+		WxString:            p.WxString,
+		FlightCategory:      p.Fltcat,
+		MetarType:           p.MetarType,
+		ElevationM:          p.Elev.float32(),
+	}
+	if p.Cover != "" {
+		result.SkyCondition = []SkyCondition{{SkyCover: p.Cover, CloudBaseFtAgl: p.Ceil.int()}}
+	}
+	return result
+}
+
+func newMETARresponse(metars []METAR) *METARresponse {
+	result := &METARresponse{}
+	result.DataSource.Name = "metars"
+	result.Request.Type = "retrieve"
+	result.Data.METAR = metars
+	result.Data.NumResults = len(metars)
+	return result
+}
+
+func newTAFresponse(tafs []TAF) *TAFresponse {
+	result := &TAFresponse{}
+	result.DataSource.Name = "tafs"
+	result.Request.Type = "retrieve"
+	result.Data.TAF = tafs
+	result.Data.NumResults = len(tafs)
+	return result
+}
+
+func newStationsInfoResponse(stations []Station) *StationsInfoResponse {
+	result := &StationsInfoResponse{}
+	result.DataSource.Name = "stations"
+	result.Request.Type = "retrieve"
+	result.Data.Station = stations
+	return result
+}
+
+// UnmarshalMetarsJSON decodes the JSON output of aviationweather.gov/api/data/metar.
+func UnmarshalMetarsJSON(input []byte) (result *METARresponse, err error) {
+	var list []apiMetar
+	if err = json.Unmarshal(input, &list); err != nil {
+		return nil, err
+	}
+	metars := make([]METAR, 0, len(list))
+	for _, m := range list {
+		metars = append(metars, m.metar())
+	}
+	return newMETARresponse(metars), nil
+}
+
+// UnmarshalMetarsGeoJSON decodes the GeoJSON output of aviationweather.gov/api/data/metar.
+// The GeoJSON properties carry only the ceiling layer of the sky condition.
+func UnmarshalMetarsGeoJSON(input []byte) (result *METARresponse, err error) {
+	var collection geoJSONCollection
+	if err = json.Unmarshal(input, &collection); err != nil {
+		return nil, err
+	}
+	metars := make([]METAR, 0, len(collection.Features))
+	for _, f := range collection.Features {
+		var p apiMetarProperties
+		if err = json.Unmarshal(f.Properties, &p); err != nil {
+			return nil, err
+		}
+		m := p.metar()
+		if len(f.Geometry.Coordinates) >= 2 {
+			m.Longitude = float32(f.Geometry.Coordinates[0])
+			m.Latitude = float32(f.Geometry.Coordinates[1])
+		}
+		metars = append(metars, m)
+	}
+	return newMETARresponse(metars), nil
+}
+
+// UnmarshalTafsJSON decodes the JSON output of aviationweather.gov/api/data/taf.
+func UnmarshalTafsJSON(input []byte) (result *TAFresponse, err error) {
+	var list []apiTaf
+	if err = json.Unmarshal(input, &list); err != nil {
+		return nil, err
+	}
+	tafs := make([]TAF, 0, len(list))
+	for _, t := range list {
+		tafs = append(tafs, t.taf())
+	}
+	return newTAFresponse(tafs), nil
+}
+
+// UnmarshalTafsGeoJSON decodes the GeoJSON output of aviationweather.gov/api/data/taf.
+func UnmarshalTafsGeoJSON(input []byte) (result *TAFresponse, err error) {
+	var collection geoJSONCollection
+	if err = json.Unmarshal(input, &collection); err != nil {
+		return nil, err
+	}
+	tafs := make([]TAF, 0, len(collection.Features))
+	for _, f := range collection.Features {
+		var p apiTafProperties
+		if err = json.Unmarshal(f.Properties, &p); err != nil {
+			return nil, err
+		}
+		t := p.taf()
+		if t.StationID == "" {
+			t.StationID = p.ID
+		}
+		if len(f.Geometry.Coordinates) >= 2 {
+			t.Longitude = float32(f.Geometry.Coordinates[0])
+			t.Latitude = float32(f.Geometry.Coordinates[1])
+		}
+		tafs = append(tafs, t)
+	}
+	return newTAFresponse(tafs), nil
+}
+
+// UnmarshalStationsInfoJSON decodes the JSON output of aviationweather.gov/api/data/stationinfo.
+func UnmarshalStationsInfoJSON(input []byte) (result *StationsInfoResponse, err error) {
+	var list []apiStation
+	if err = json.Unmarshal(input, &list); err != nil {
+		return nil, err
+	}
+	stations := make([]Station, 0, len(list))
+	for _, s := range list {
+		stations = append(stations, s.station())
+	}
+	return newStationsInfoResponse(stations), nil
+}
+
+// UnmarshalStationsInfoGeoJSON decodes the GeoJSON output of aviationweather.gov/api/data/stationinfo.
+func UnmarshalStationsInfoGeoJSON(input []byte) (result *StationsInfoResponse, err error) {
+	var collection geoJSONCollection
+	if err = json.Unmarshal(input, &collection); err != nil {
+		return nil, err
+	}
+	stations := make([]Station, 0, len(collection.Features))
+	for _, f := range collection.Features {
+		var s apiStation
+		if err = json.Unmarshal(f.Properties, &s); err != nil {
+			return nil, err
+		}
+		station := s.station()
+		if len(f.Geometry.Coordinates) >= 2 {
+			station.Longitude = float32(f.Geometry.Coordinates[0])
+			station.Latitude = float32(f.Geometry.Coordinates[1])
+		}
+		stations = append(stations, station)
+	}
+	return newStationsInfoResponse(stations), nil
+}
@@ -0,0 +1,130 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnmarshalMetarsJSON(t *testing.T) {
+	Convey("Unmarshal METAR JSON should work correctly", t, func() {
+		input := []byte(`[{"metar_id":1,"icaoId":"ULLI","receiptTime":"2019-06-10 08:02:11","obsTime":1560153600,"reportTime":"2019-06-10 08:00:00","temp":20,"dewp":11,"wdir":230,"wspd":14,"wgst":null,"visib":"6+","altim":1022,"slp":null,"qcField":16,"wxString":null,"presTend":null,"maxT":null,"minT":null,"maxT24":null,"minT24":null,"precip":null,"pcp3hr":null,"pcp6hr":null,"pcp24hr":null,"snow":null,"vertVis":null,"metarType":"METAR","rawOb":"ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG","mostRecent":1,"lat":59.8,"lon":30.27,"elev":4,"prior":6,"name":"St Petersburg/Pulkovo, LE, RU","fltCat":"VFR","clouds":[{"cover":"FEW","base":4000}]},{"icaoId":"URSS","obsTime":1559899800,"temp":19,"dewp":13,"wdir":"VRB","wspd":12,"wgst":21,"visib":6.21,"altim":1012,"qcField":1,"wxString":"-TSRA","metarType":"SPECI","rawOb":"URSS 070930Z VRB06G11MPS 9999 -TSRA BKN007 SCT030CB 19/13 Q1012","lat":43.45,"lon":39.95,"elev":16,"clouds":[{"cover":"BKN","base":700},{"cover":"SCT","base":3000}]}]`)
+		expected := []METAR{{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG",
+			StationID:       "ULLI",
+			ObservationTime: time.Date(2019, 06, 10, 8, 0, 0, 0, time.UTC),
+			Latitude:        59.8, Longitude: 30.27, TempC: 20, DewpointC: 11, WindDirDegrees: 230, WindSpeedKt: 14, VisibilityStatuteMi: 6, AltimInHg: 30.177166,
+			QualityControlFlags: QualityControlFlags{NoSignal: true},
+			SkyCondition:        []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}},
+			FlightCategory:      "VFR", MetarType: "METAR", ElevationM: 4},
+			{RawText: "URSS 070930Z VRB06G11MPS 9999 -TSRA BKN007 SCT030CB 19/13 Q1012",
+				StationID:       "URSS",
+				ObservationTime: time.Date(2019, 06, 7, 9, 30, 0, 0, time.UTC),
+				Latitude:        43.45, Longitude: 39.95, TempC: 19, DewpointC: 13, WindDirDegrees: 0, WindSpeedKt: 12, WindGustKt: 21, VisibilityStatuteMi: 6.21, AltimInHg: 29.881891,
+				QualityControlFlags: QualityControlFlags{Corrected: true},
+				WxString:            "-TSRA",
+				SkyCondition:        []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 700}, {SkyCover: "SCT", CloudBaseFtAgl: 3000}},
+				MetarType:           "SPECI", ElevationM: 16}}
+
+		mr, err := UnmarshalMetarsJSON(input)
+		Convey("err must be nil", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("reports should be mapped onto the METAR model", func() {
+			So(mr.Data.NumResults, ShouldEqual, 2)
+			So(mr.DataSource.Name, ShouldEqual, "metars")
+			So(mr.Data.METAR, ShouldResemble, expected)
+		})
+	})
+	Convey("Malformed METAR JSON should return an error", t, func() {
+		_, err := UnmarshalMetarsJSON([]byte(`{"error":"bad request"}`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestUnmarshalMetarsGeoJSON(t *testing.T) {
+	Convey("Unmarshal METAR GeoJSON should work correctly", t, func() {
+		input := []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","id":"ULLI","properties":{"id":"ULLI","site":"St Petersburg/Pulkovo","prior":6,"obsTime":"2019-06-10T08:00:00Z","temp":20,"dewp":11,"wspd":14,"wdir":230,"cover":"FEW","ceil":4000,"visib":"6+","fltcat":"VFR","altim":1022,"rawOb":"ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG"},"geometry":{"type":"Point","coordinates":[30.27,59.8]}}]}`)
+		mr, err := UnmarshalMetarsGeoJSON(input)
+		So(err, ShouldBeNil)
+		So(mr.Data.METAR, ShouldHaveLength, 1)
+		m := mr.Data.METAR[0]
+		So(m.StationID, ShouldEqual, "ULLI")
+		So(m.ObservationTime, ShouldResemble, time.Date(2019, 06, 10, 8, 0, 0, 0, time.UTC))
+		So(m.Latitude, ShouldEqual, float32(59.8))
+		So(m.Longitude, ShouldEqual, float32(30.27))
+		So(m.WindDirDegrees, ShouldEqual, 230)
+		So(m.AltimInHg, ShouldEqual, float32(30.177166))
+		So(m.SkyCondition, ShouldResemble, []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}})
+		So(m.FlightCategory, ShouldEqual, "VFR")
+	})
+}
+
+func TestUnmarshalTafsJSON(t *testing.T) {
+	Convey("Unmarshal TAF JSON should work correctly", t, func() {
+		input := []byte(`[{"tafId":1,"icaoId":"URSS","dbPopTime":"2019-06-07 05:02:00","bulletinTime":"2019-06-07 05:00:00","issueTime":"2019-06-07 04:56:00","validTimeFrom":1559887200,"validTimeTo":1559973600,"rawTAF":"TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040 BECMG 0708/0709 28006G11MPS SCT030CB PROB30 TEMPO 0709/0717 -TSRA","mostRecent":1,"remarks":"","lat":43.45,"lon":39.95,"elev":16,"prior":6,"fcsts":[{"timeGroup":0,"timeFrom":1559887200,"timeTo":1559894400,"timeBec":null,"fcstChange":null,"probability":null,"wdir":230,"wspd":10,"wgst":null,"wshearHgt":null,"wshearDir":null,"wshearSpd":null,"visib":"6+","altim":null,"vertVis":null,"wxString":null,"notDecoded":null,"clouds":[{"cover":"FEW","base":4000,"type":null}],"icgTurb":[{"var":"TURB","intensity":5,"minAlt":1000,"maxAlt":7000}],"temp":[{"validTime":1559908800,"sfcTemp":24,"maxOrMin":"MAX"}]},{"timeGroup":1,"timeFrom":1559898000,"timeTo":1559926800,"timeBec":null,"fcstChange":"TEMPO","probability":30,"wdir":null,"wspd":null,"visib":null,"wxString":"-TSRA","clouds":[]}]}]`)
+		tr, err := UnmarshalTafsJSON(input)
+		So(err, ShouldBeNil)
+		So(tr.Data.TAF, ShouldHaveLength, 1)
+		taf := tr.Data.TAF[0]
+		So(taf.StationID, ShouldEqual, "URSS")
+		So(taf.IssueTime, ShouldResemble, time.Date(2019, 06, 7, 4, 56, 0, 0, time.UTC))
+		So(taf.BulletinTime, ShouldResemble, time.Date(2019, 06, 7, 5, 0, 0, 0, time.UTC))
+		So(taf.ValidTimeFrom, ShouldResemble, time.Date(2019, 06, 7, 6, 0, 0, 0, time.UTC))
+		So(taf.ValidTimeTo, ShouldResemble, time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC))
+		So(taf.Forecast, ShouldHaveLength, 2)
+		So(taf.Forecast[0], ShouldResemble, Forecast{
+			FcstTimeFrom:        time.Date(2019, 06, 7, 6, 0, 0, 0, time.UTC),
+			FcstTimeTo:          time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC),
+			WindDirDegrees:      230,
+			WindSpeedKt:         10,
+			VisibilityStatuteMi: 6,
+			SkyCondition:        []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}},
+			TurbulenceCondition: []TurbulenceCondition{{TurbulenceIntensity: "5", TurbulenceMinAltFtAgl: 1000, TurbulenceMaxAltFtAgl: 7000}},
+			Temperature:         []Temperature{{ValidTime: time.Date(2019, 06, 7, 12, 0, 0, 0, time.UTC), MaxTempC: "24"}},
+		})
+		So(taf.Forecast[1].ChangeIndicator, ShouldEqual, "TEMPO")
+		So(taf.Forecast[1].Probability, ShouldEqual, "30")
+		So(taf.Forecast[1].WxString, ShouldEqual, "-TSRA")
+	})
+	Convey("Unmarshal TAF GeoJSON should take coordinates from the geometry", t, func() {
+		input := []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"id":"URSS","issueTime":"2019-06-07T04:56:00Z","validTimeFrom":"2019-06-07T06:00:00Z","validTimeTo":"2019-06-08T06:00:00Z","rawTAF":"TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040","fcsts":[{"timeFrom":1559887200,"timeTo":1559973600,"wdir":230,"wspd":10,"visib":"6+","clouds":[{"cover":"FEW","base":4000}]}]},"geometry":{"type":"Point","coordinates":[39.95,43.45]}}]}`)
+		tr, err := UnmarshalTafsGeoJSON(input)
+		So(err, ShouldBeNil)
+		So(tr.Data.TAF, ShouldHaveLength, 1)
+		taf := tr.Data.TAF[0]
+		So(taf.StationID, ShouldEqual, "URSS")
+		So(taf.Latitude, ShouldEqual, float32(43.45))
+		So(taf.Longitude, ShouldEqual, float32(39.95))
+		So(taf.ValidTimeTo, ShouldResemble, time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC))
+		So(taf.Forecast, ShouldHaveLength, 1)
+		So(taf.Forecast[0].SkyCondition, ShouldResemble, []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}})
+
+		decoded, err := decodeTAFs(input)
+		So(err, ShouldBeNil)
+		So(decoded.Data.TAF, ShouldResemble, tr.Data.TAF)
+	})
+}
+
+func TestUnmarshalStationsInfoJSON(t *testing.T) {
+	Convey("Unmarshal stations info JSON should work correctly", t, func() {
+		input := []byte(`[{"id":"KABR","icaoId":"KABR","iataId":"ABR","faaId":"ABR","wmoId":"72659","site":"Aberdeen Rgnl","lat":45.4536,"lon":-98.4136,"elev":397,"state":"SD","country":"US","priority":1,"siteType":["METAR","TAF","NEXRAD","WFO","RAOB"]},{"id":"PHNL","icaoId":"PHNL","site":"Honolulu Intl","lat":21.3187,"lon":-157.9224,"elev":4,"state":"HI","country":"US","siteType":"METAR,TAF"}]`)
+		si, err := UnmarshalStationsInfoJSON(input)
+		So(err, ShouldBeNil)
+		So(si.Data.Station, ShouldResemble, []Station{
+			{StationID: "KABR", WMOID: "72659", Latitude: 45.4536, Longitude: -98.4136, ElevationM: 397, Site: "Aberdeen Rgnl", State: "SD", Country: "US",
+				SiteType: siteType{METAR: true, TAF: true, WFOoffice: true, NEXRAD: true, Rawinsonde: true}},
+			{StationID: "PHNL", Latitude: 21.3187, Longitude: -157.9224, ElevationM: 4, Site: "Honolulu Intl", State: "HI", Country: "US",
+				SiteType: siteType{METAR: true, TAF: true}},
+		})
+	})
+	Convey("Unmarshal stations info GeoJSON should take coordinates from the geometry", t, func() {
+		input := []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"id":"KSEA","site":"Seattle-Tacoma Intl","elev":136,"country":"US","siteType":["METAR","TAF"]},"geometry":{"type":"Point","coordinates":[-122.32,47.45]}}]}`)
+		si, err := UnmarshalStationsInfoGeoJSON(input)
+		So(err, ShouldBeNil)
+		So(si.Data.Station, ShouldResemble, []Station{
+			{StationID: "KSEA", Latitude: 47.45, Longitude: -122.32, ElevationM: 136, Site: "Seattle-Tacoma Intl", Country: "US",
+				SiteType: siteType{METAR: true, TAF: true}},
+		})
+	})
+}
@@ -0,0 +1,277 @@
+package addstogo
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const archiveDateLayout = "20060102"
+
+// Archive keeps decoded reports on disk in gzipped XML files, one or more per UTC day.
+// METARs are partitioned by observation time, TAFs by issue time.
+// It is safe for concurrent use within one process.
+type Archive struct {
+	// MaxFileBytes starts a new file of the day once the current one grows beyond it. Zero means no limit.
+	MaxFileBytes int64
+	// Retention removes the days older than it on every write. Zero keeps everything.
+	Retention time.Duration
+
+	dir string
+	mu  sync.Mutex
+}
+
+// OpenArchive opens the archive in the directory, creating it if needed.
+func OpenArchive(dir string) (*Archive, error) {
+	for _, kind := range []string{"metars", "tafs"} {
+		if err := os.MkdirAll(filepath.Join(dir, kind), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// WriteMETARs appends the observations to the archive.
+func (a *Archive) WriteMETARs(metars []METAR) error {
+	days := make(map[string][]interface{})
+	for _, m := range metars {
+		day := m.ObservationTime.UTC().Format(archiveDateLayout)
+		days[day] = append(days[day], m)
+	}
+	return a.write("metars", days)
+}
+
+// WriteTAFs appends the forecasts to the archive.
+func (a *Archive) WriteTAFs(tafs []TAF) error {
+	days := make(map[string][]interface{})
+	for _, t := range tafs {
+		day := t.IssueTime.UTC().Format(archiveDateLayout)
+		days[day] = append(days[day], t)
+	}
+	return a.write("tafs", days)
+}
+
+func (a *Archive) write(kind string, days map[string][]interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for day, reports := range days {
+		if err := a.appendDay(kind, day, reports); err != nil {
+			return err
+		}
+	}
+	if a.Retention > 0 {
+		return a.prune(time.Now().Add(-a.Retention))
+	}
+	return nil
+}
+
+// appendDay writes the reports as a new gzip member at the end of the current file of the day.
+func (a *Archive) appendDay(kind, day string, reports []interface{}) error {
+	files, err := a.dayFiles(kind, day)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(a.dir, kind, day+".xml.gz")
+	if n := len(files); n > 0 {
+		name = files[n-1]
+		if info, err := os.Stat(name); err == nil && a.MaxFileBytes > 0 && info.Size() >= a.MaxFileBytes {
+			name = filepath.Join(a.dir, kind, fmt.Sprintf("%s-%d.xml.gz", day, n))
+		}
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	enc := xml.NewEncoder(zw)
+	for _, r := range reports {
+		if err = enc.Encode(r); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = enc.Flush()
+	}
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// dayFiles returns the files of the day in the order they were written.
+func (a *Archive) dayFiles(kind, day string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(a.dir, kind, day+"*.xml.gz"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return archiveFileSeq(files[i]) < archiveFileSeq(files[j]) })
+	return files, nil
+}
+
+// archiveFileSeq returns the rotation number of the file, 0 for the first file of the day.
+func archiveFileSeq(name string) int {
+	base := strings.TrimSuffix(filepath.Base(name), ".xml.gz")
+	if i := strings.IndexByte(base, '-'); i >= 0 {
+		n, _ := strconv.Atoi(base[i+1:])
+		return n
+	}
+	return 0
+}
+
+// archiveDays returns the partitions covering [from, to).
+func archiveDays(from, to time.Time) []string {
+	var days []string
+	from = from.UTC()
+	for d := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC); d.Before(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(archiveDateLayout))
+	}
+	return days
+}
+
+// read decodes every element with the local name from the files of the days, in the order written.
+func (a *Archive) read(kind, element string, from, to time.Time, decode func(d *xml.Decoder, start *xml.StartElement) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, day := range archiveDays(from, to) {
+		files, err := a.dayFiles(kind, day)
+		if err != nil {
+			return err
+		}
+		for _, name := range files {
+			if err := readArchiveFile(name, element, decode); err != nil {
+				return fmt.Errorf("addstogo: %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func readArchiveFile(name, element string, decode func(d *xml.Decoder, start *xml.StartElement) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	d := xml.NewDecoder(zr)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == element {
+			if err := decode(d, &start); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// METARs replays the observations of the station made in [from, to), oldest first.
+// An empty station means all stations. Of reports written more than once the last one wins.
+func (a *Archive) METARs(station string, from, to time.Time) (*METARresponse, error) {
+	type key struct {
+		station string
+		time    int64
+	}
+	index := make(map[key]int)
+	var metars []METAR
+	err := a.read("metars", "METAR", from, to, func(d *xml.Decoder, start *xml.StartElement) error {
+		var m METAR
+		if err := d.DecodeElement(&m, start); err != nil {
+			return err
+		}
+		if station != "" && m.StationID != station || m.ObservationTime.Before(from) || !m.ObservationTime.Before(to) {
+			return nil
+		}
+		k := key{m.StationID, m.ObservationTime.UnixNano()}
+		if i, ok := index[k]; ok {
+			metars[i] = m
+		} else {
+			index[k] = len(metars)
+			metars = append(metars, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(metars, func(i, j int) bool { return metars[i].ObservationTime.Before(metars[j].ObservationTime) })
+	return newMETARresponse(metars), nil
+}
+
+// TAFs replays the forecasts of the station issued in [from, to), oldest first.
+// An empty station means all stations. Of forecasts written more than once the last one wins.
+func (a *Archive) TAFs(station string, from, to time.Time) (*TAFresponse, error) {
+	type key struct {
+		station string
+		time    int64
+	}
+	index := make(map[key]int)
+	var tafs []TAF
+	err := a.read("tafs", "TAF", from, to, func(d *xml.Decoder, start *xml.StartElement) error {
+		var t TAF
+		if err := d.DecodeElement(&t, start); err != nil {
+			return err
+		}
+		if station != "" && t.StationID != station || t.IssueTime.Before(from) || !t.IssueTime.Before(to) {
+			return nil
+		}
+		k := key{t.StationID, t.IssueTime.UnixNano()}
+		if i, ok := index[k]; ok {
+			tafs[i] = t
+		} else {
+			index[k] = len(tafs)
+			tafs = append(tafs, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(tafs, func(i, j int) bool { return tafs[i].IssueTime.Before(tafs[j].IssueTime) })
+	return newTAFresponse(tafs), nil
+}
+
+// Prune removes the days that ended before the time.
+func (a *Archive) Prune(before time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.prune(before)
+}
+
+func (a *Archive) prune(before time.Time) error {
+	before = before.UTC()
+	cutoff := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, time.UTC).Format(archiveDateLayout)
+	for _, kind := range []string{"metars", "tafs"} {
+		files, err := filepath.Glob(filepath.Join(a.dir, kind, "*.xml.gz"))
+		if err != nil {
+			return err
+		}
+		for _, name := range files {
+			if day := filepath.Base(name); len(day) >= len(archiveDateLayout) && day[:len(archiveDateLayout)] < cutoff {
+				if err := os.Remove(name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
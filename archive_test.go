@@ -0,0 +1,82 @@
+package addstogo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArchive(t *testing.T) {
+	Convey("Archive in a temporary directory", t, func() {
+		dir, err := ioutil.TempDir("", "addstogo")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		a, err := OpenArchive(dir)
+		So(err, ShouldBeNil)
+
+		t0 := time.Date(2019, 6, 7, 23, 30, 0, 0, time.UTC)
+		metar := METAR{RawText: "URSS 072330Z 05004MPS CAVOK 18/12 Q1012", StationID: "URSS", ObservationTime: t0,
+			Latitude: 43.45, Longitude: 39.95, TempC: 18, DewpointC: 12, WindDirDegrees: 50, WindSpeedKt: 8,
+			SkyCondition: []SkyCondition{{SkyCover: "CAVOK"}}, FlightCategory: "VFR", MetarType: "METAR"}
+		So(a.WriteMETARs([]METAR{
+			metar,
+			{StationID: "URSS", ObservationTime: t0.Add(30 * time.Minute), RawText: "URSS 080000Z"},
+			{StationID: "ULLI", ObservationTime: t0.Add(30 * time.Minute), RawText: "ULLI 080000Z"},
+		}), ShouldBeNil)
+		taf := TAF{RawText: "TAF URSS 072300Z 0800/0824 05005MPS CAVOK", StationID: "URSS", IssueTime: t0.Add(-30 * time.Minute),
+			ValidTimeFrom: t0.Add(30 * time.Minute), ValidTimeTo: t0.Add(24*time.Hour + 30*time.Minute),
+			Forecast: []Forecast{{FcstTimeFrom: t0.Add(30 * time.Minute), FcstTimeTo: t0.Add(24*time.Hour + 30*time.Minute), WindDirDegrees: 50, WindSpeedKt: 10}}}
+		So(a.WriteTAFs([]TAF{taf}), ShouldBeNil)
+
+		Convey("should partition the files by day", func() {
+			files, _ := filepath.Glob(filepath.Join(dir, "metars", "*"))
+			So(len(files), ShouldEqual, 2)
+			So(filepath.Base(files[0]), ShouldEqual, "20190607.xml.gz")
+		})
+		Convey("should replay the reports unchanged", func() {
+			r, err := a.METARs("URSS", t0, t0.Add(time.Minute))
+			So(err, ShouldBeNil)
+			So(r.Data.NumResults, ShouldEqual, 1)
+			So(r.Data.METAR[0], ShouldResemble, metar)
+			tafs, err := a.TAFs("", t0.Add(-time.Hour), t0)
+			So(err, ShouldBeNil)
+			So(tafs.Data.TAF, ShouldResemble, []TAF{taf})
+		})
+		Convey("should query by station and time range across days", func() {
+			r, _ := a.METARs("", t0, t0.Add(time.Hour))
+			So(r.Data.NumResults, ShouldEqual, 3)
+			r, _ = a.METARs("URSS", t0.Add(time.Minute), t0.Add(time.Hour))
+			So(r.Data.NumResults, ShouldEqual, 1)
+			So(r.Data.METAR[0].RawText, ShouldEqual, "URSS 080000Z")
+		})
+		Convey("should let the last written report win", func() {
+			corrected := metar
+			corrected.RawText = "URSS 072330Z COR 05004MPS CAVOK 18/11 Q1012"
+			So(a.WriteMETARs([]METAR{corrected}), ShouldBeNil)
+			r, _ := a.METARs("URSS", t0, t0.Add(time.Minute))
+			So(r.Data.NumResults, ShouldEqual, 1)
+			So(r.Data.METAR[0].RawText, ShouldEqual, corrected.RawText)
+		})
+		Convey("should rotate files over the size limit", func() {
+			a.MaxFileBytes = 1
+			So(a.WriteMETARs([]METAR{{StationID: "UUEE", ObservationTime: t0.Add(-time.Hour)}}), ShouldBeNil)
+			So(a.WriteMETARs([]METAR{{StationID: "UUEE", ObservationTime: t0.Add(-2 * time.Hour)}}), ShouldBeNil)
+			files, _ := filepath.Glob(filepath.Join(dir, "metars", "20190607*"))
+			So(len(files), ShouldEqual, 3)
+			r, _ := a.METARs("UUEE", t0.Add(-3*time.Hour), t0)
+			So(r.Data.NumResults, ShouldEqual, 2)
+			So(r.Data.METAR[0].ObservationTime, ShouldResemble, t0.Add(-2*time.Hour))
+		})
+		Convey("should prune days past retention", func() {
+			So(a.Prune(t0.Add(time.Hour)), ShouldBeNil)
+			r, _ := a.METARs("", t0.Add(-24*time.Hour), t0.Add(24*time.Hour))
+			So(r.Data.NumResults, ShouldEqual, 2)
+			tafs, _ := a.TAFs("", t0.Add(-24*time.Hour), t0.Add(24*time.Hour))
+			So(tafs.Data.TAF, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,326 @@
+package addstogo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urkk/addstogo/wmm"
+)
+
+var (
+	phoneticAlphabet = []string{"Alfa", "Bravo", "Charlie", "Delta", "Echo", "Foxtrot", "Golf", "Hotel", "India", "Juliett",
+		"Kilo", "Lima", "Mike", "November", "Oscar", "Papa", "Quebec", "Romeo", "Sierra", "Tango", "Uniform", "Victor",
+		"Whiskey", "Xray", "Yankee", "Zulu"}
+	spokenDigitNames = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "niner"}
+	runwaySides      = map[string]string{"L": "left", "C": "center", "R": "right"}
+)
+
+// spokenDigits spells every digit of the text, e.g. "230" as "two three zero". Other characters are dropped.
+func spokenDigits(s string) string {
+	var words []string
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			words = append(words, spokenDigitNames[c-'0'])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func spokenNumber(n int) string {
+	if n < 0 {
+		return "minus " + spokenDigits(strconv.Itoa(-n))
+	}
+	return spokenDigits(strconv.Itoa(n))
+}
+
+// spokenHundreds reads a height or distance in thousands and hundreds, e.g. 1200 as "one thousand two hundred".
+func spokenHundreds(n int) string {
+	n = (n + 50) / 100 * 100
+	var words []string
+	if n >= 1000 {
+		words = append(words, spokenNumber(n/1000), "thousand")
+	}
+	if h := n % 1000 / 100; h > 0 || n == 0 {
+		words = append(words, spokenDigitNames[h], "hundred")
+	}
+	return strings.Join(words, " ")
+}
+
+func spokenRunway(ident string) string {
+	number := strings.TrimRight(ident, "LCR")
+	s := "runway " + spokenDigits(number)
+	if side, ok := runwaySides[strings.TrimPrefix(ident, number)]; ok {
+		s += " " + side
+	}
+	return s
+}
+
+// spokenStatuteMiles reads a visibility in miles with the fractions used in reports.
+func spokenStatuteMiles(sm float64) string {
+	whole, fraction := math.Modf(sm)
+	var parts []string
+	if whole > 0 {
+		parts = append(parts, spokenNumber(int(whole)))
+	}
+	switch q := int(math.Round(fraction * 8)); q {
+	case 0:
+	case 2:
+		parts = append(parts, "one quarter")
+	case 4:
+		parts = append(parts, "one half")
+	case 6:
+		parts = append(parts, "three quarters")
+	default:
+		parts = append(parts, spokenNumber(q)+" eighths")
+	}
+	if len(parts) == 0 {
+		return "zero"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// ATISInformation is one broadcast of an ATIS.
+type ATISInformation struct {
+	Letter byte
+	// Runway is the runway in use, empty without runways.
+	Runway string
+	METAR  METAR
+	Text   string
+}
+
+// ATIS generates automatic terminal information service broadcasts from the latest METAR of an aerodrome.
+// The information letter advances with every new report, from A to Z and back to A.
+type ATIS struct {
+	// Name is the aerodrome name spoken in the broadcast, the station identifier when empty.
+	Name string
+	// Runways and Limits select the runway in use with RankRunways against the true wind. The headings are true,
+	// as Airport.RunwayDirections and ParseRunways with the station declination give them.
+	Runways []Runway
+	Limits  RunwayLimits
+	// MagneticWind reads wind directions relative to magnetic north, as controllers give them.
+	// It does not change the runway in use.
+	MagneticWind bool
+	// Remarks are read at the end of every broadcast.
+	Remarks []string
+
+	mu      sync.Mutex
+	letter  int
+	current *ATISInformation
+}
+
+// NewATIS returns an ATIS for the runways of an aerodrome, starting at information Alfa.
+func NewATIS(name string, runways []Runway) *ATIS {
+	return &ATIS{Name: name, Runways: runways, Limits: RunwayLimits{CrosswindKt: 20, TailwindKt: 5}}
+}
+
+// SetLetter sets the letter of the next new information.
+func (a *ATIS) SetLetter(letter byte) error {
+	if letter < 'A' || letter > 'Z' {
+		return fmt.Errorf("addstogo: invalid ATIS letter %q", letter)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.letter = int(letter - 'A')
+	return nil
+}
+
+// Current returns the latest information, false before the first report.
+func (a *ATIS) Current() (ATISInformation, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.current == nil {
+		return ATISInformation{}, false
+	}
+	return *a.current, true
+}
+
+// Update returns the information for the latest report. A report differing from the previous one
+// takes the next letter, the same report is broadcast again with the same letter.
+func (a *ATIS) Update(m METAR) ATISInformation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.current != nil && a.current.METAR.RawText == m.RawText && a.current.METAR.ObservationTime.Equal(m.ObservationTime) {
+		return *a.current
+	}
+	info := ATISInformation{Letter: byte('A' + a.letter), METAR: m}
+	a.letter = (a.letter + 1) % len(phoneticAlphabet)
+	if len(a.Runways) > 0 {
+		info.Runway = RankRunways(a.Runways, m.Wind(), a.Limits)[0].Ident
+	}
+	info.Text = a.script(info)
+	a.current = &info
+	return info
+}
+
+func (a *ATIS) script(info ATISInformation) string {
+	m := info.METAR
+	name := a.Name
+	if name == "" {
+		name = m.StationID
+	}
+	phonetic := phoneticAlphabet[info.Letter-'A']
+	sentences := []string{fmt.Sprintf("%s information %s, %s zulu", name, phonetic, spokenTime(m.ObservationTime))}
+	if info.Runway != "" {
+		sentences = append(sentences, "Runway in use "+strings.TrimPrefix(spokenRunway(info.Runway), "runway "))
+	}
+	sentences = append(sentences, spokenObservation(m, a.wind(m))...)
+	for _, t := range strings.Fields(m.RawText) {
+		if t == "RMK" {
+			break
+		}
+		if t == "NOSIG" {
+			sentences = append(sentences, "No significant change")
+		}
+	}
+	if len(a.Remarks) > 0 {
+		sentences = append(sentences, "Remarks, "+strings.Join(a.Remarks, ", "))
+	}
+	sentences = append(sentences, fmt.Sprintf("Advise on initial contact you have information %s", phonetic))
+	for i, s := range sentences {
+		sentences[i] = strings.ToUpper(s[:1]) + s[1:]
+	}
+	return strings.Join(sentences, ". ") + "."
+}
+
+// wind returns the wind of the report, relative to magnetic north when the ATIS reads magnetic winds.
+func (a *ATIS) wind(m METAR) Wind {
+	w := m.Wind()
+	if a.MagneticWind && !w.Variable && w.DirDegrees != 0 {
+		decl := Declination(m, float64(m.ElevationM), m.ObservationTime)
+		w.DirDegrees, _ = m.MagneticWindDir()
+		w.VarFromDeg = int(math.Round(wmm.ToMagnetic(float64(w.VarFromDeg), decl)))
+		w.VarToDeg = int(math.Round(wmm.ToMagnetic(float64(w.VarToDeg), decl)))
+	}
+	return w
+}
+
+// spokenObservation returns the sentences of the wind, visibility, weather, clouds, temperatures and pressure of a report.
+func spokenObservation(m METAR, w Wind) []string {
+	var sentences []string
+	g := groupsOf(&m)
+	if g.wind {
+		sentences = append(sentences, spokenWind(w, "knots"))
+	}
+	cavok := strings.Contains(" "+m.RawText+" ", " CAVOK ")
+	metric, _ := visibilityInMetres(m.RawText)
+	if cavok {
+		sentences = append(sentences, "CAV OK")
+	} else if g.visibility {
+		sentences = append(sentences, spokenVisibility(decimal(m.VisibilityStatuteMi), metric))
+	}
+	if m.WxString != "" {
+		sentences = append(sentences, English.describeWeather(m.WxString))
+	}
+	if !cavok {
+		sentences = append(sentences, spokenClouds(m.SkyCondition, m.VertVisFt, m.RawText)...)
+	}
+	if g.temperature {
+		s := "Temperature " + spokenNumber(int(math.Round(decimal(m.TempC))))
+		if g.dewpoint {
+			s += ", dew point " + spokenNumber(int(math.Round(decimal(m.DewpointC))))
+		}
+		sentences = append(sentences, s)
+	}
+	if g.altimeter {
+		if hectopascalsReported(m.RawText) {
+			sentences = append(sentences, "QNH "+spokenNumber(int(math.Round(float64(m.AltimInHg)/inHgPerHPa))))
+		} else {
+			sentences = append(sentences, "Altimeter "+spokenDigits(fmt.Sprintf("%.2f", decimal(m.AltimInHg))))
+		}
+	}
+	return sentences
+}
+
+// spokenWind reads a wind with the speeds in the unit.
+func spokenWind(w Wind, unit string) string {
+	if w.DirDegrees == 0 && w.SpeedKt == 0 {
+		return "Wind calm"
+	}
+	var s string
+	if w.Variable {
+		s = "Wind variable at " + spokenNumber(w.SpeedKt)
+	} else {
+		s = fmt.Sprintf("Wind %s at %s", spokenDigits(fmt.Sprintf("%03d", w.DirDegrees)), spokenNumber(w.SpeedKt))
+	}
+	if w.GustKt > 0 {
+		s += " gusting " + spokenNumber(w.GustKt)
+	}
+	s += " " + unit
+	if w.VarFromDeg != w.VarToDeg {
+		s += fmt.Sprintf(", varying between %s and %s", spokenDigits(fmt.Sprintf("%03d", w.VarFromDeg)), spokenDigits(fmt.Sprintf("%03d", w.VarToDeg)))
+	}
+	return s
+}
+
+func spokenVisibility(sm float64, metric bool) string {
+	if !metric {
+		if sm >= 10 {
+			return "Visibility one zero"
+		}
+		return "Visibility " + spokenStatuteMiles(sm)
+	}
+	if sm >= 6.2 {
+		return "Visibility one zero kilometres or more"
+	}
+	return spokenMetres(int(sm * 1609.344))
+}
+
+func spokenMetres(m int) string {
+	if m >= 5000 {
+		return fmt.Sprintf("Visibility %s kilometres", spokenNumber(int(math.Round(float64(m)/1000))))
+	}
+	return fmt.Sprintf("Visibility %s metres", spokenHundreds(m))
+}
+
+// spokenClouds returns the sentences of the cloud layers. Cloud types missing from the layers are taken from the raw report.
+func spokenClouds(sky []SkyCondition, vertVisFt int, raw string) []string {
+	covers := map[string]string{"FEW": "few", "SCT": "scattered", "BKN": "broken", "OVC": "overcast"}
+	types := make(map[int]string)
+	for _, token := range strings.Fields(raw) {
+		if g := cloudGroup.FindStringSubmatch(token); g != nil && g[3] != "" {
+			ft, _ := strconv.Atoi(g[2])
+			types[ft*100] = g[3]
+		}
+	}
+	var sentences, layers []string
+	for _, layer := range sky {
+		switch layer.SkyCover {
+		case "SKC", "CLR":
+			sentences = append(sentences, "Sky clear")
+		case "NSC", "NCD":
+			sentences = append(sentences, "No significant cloud")
+		case "OVX":
+			sentences = append(sentences, "Sky obscured")
+		default:
+			cover, ok := covers[layer.SkyCover]
+			if !ok {
+				continue
+			}
+			s := cover + " " + spokenHundreds(layer.CloudBaseFtAgl) + " feet"
+			cloudType := layer.CloudType
+			if cloudType == "" {
+				cloudType = types[layer.CloudBaseFtAgl]
+			}
+			if name, ok := cloudTypes[cloudType]; ok {
+				s += " " + name
+			}
+			layers = append(layers, s)
+		}
+	}
+	if len(layers) > 0 {
+		sentences = append(sentences, "Clouds "+strings.Join(layers, ", "))
+	}
+	if vertVisFt > 0 {
+		sentences = append(sentences, "Vertical visibility "+spokenHundreds(vertVisFt)+" feet")
+	}
+	return sentences
+}
+
+// spokenTime reads a time as four digits, e.g. "zero niner three zero".
+func spokenTime(t time.Time) string {
+	return spokenDigits(t.UTC().Format("1504"))
+}
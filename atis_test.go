@@ -0,0 +1,114 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestATIS(t *testing.T) {
+	Convey("Numbers should be spoken digit by digit", t, func() {
+		So(spokenDigits("230"), ShouldEqual, "two three zero")
+		So(spokenNumber(-9), ShouldEqual, "minus niner")
+		So(spokenHundreds(700), ShouldEqual, "seven hundred")
+		So(spokenHundreds(1200), ShouldEqual, "one thousand two hundred")
+		So(spokenHundreds(12000), ShouldEqual, "one two thousand")
+		So(spokenStatuteMiles(1.5), ShouldEqual, "one and one half")
+		So(spokenStatuteMiles(0.25), ShouldEqual, "one quarter")
+		So(spokenRunway("16L"), ShouldEqual, "runway one six left")
+	})
+	ulli := METAR{
+		RawText:         "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG",
+		StationID:       "ULLI",
+		ObservationTime: time.Date(2019, 6, 10, 8, 0, 0, 0, time.UTC),
+		WindDirDegrees:  230, WindSpeedKt: 14, VisibilityStatuteMi: 6.21, TempC: 20, DewpointC: 11, AltimInHg: 30.18,
+		SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}},
+	}
+	Convey("An ATIS should read the latest METAR", t, func() {
+		runways, _ := ParseRunways("10L/28R 10R/28L", Declination(Station{Latitude: 59.8, Longitude: 30.27}, 4, ulli.ObservationTime))
+		a := NewATIS("Pulkovo", runways)
+		info := a.Update(ulli)
+		So(info.Letter, ShouldEqual, 'A')
+		So(info.Runway, ShouldEqual, "28R")
+		So(info.Text, ShouldEqual, "Pulkovo information Alfa, zero eight zero zero zulu. Runway in use two eight right. "+
+			"Wind two three zero at one four knots, varying between two one zero and two seven zero. "+
+			"Visibility one zero kilometres or more. Clouds few four thousand feet. Temperature two zero, dew point one one. "+
+			"QNH one zero two two. No significant change. Advise on initial contact you have information Alfa.")
+	})
+	Convey("US reports should use US conventions and magnetic winds", t, func() {
+		m := METAR{
+			RawText:         "KJFK 011251Z 23014G25KT 3/4SM +SHRA BR BKN008 OVC012CB M01/M03 A2992",
+			StationID:       "KJFK",
+			ObservationTime: time.Date(2019, 7, 1, 12, 51, 0, 0, time.UTC),
+			Latitude:        40.64, Longitude: -73.78,
+			WindDirDegrees: 230, WindSpeedKt: 14, WindGustKt: 25, VisibilityStatuteMi: 0.75, WxString: "+SHRA BR",
+			SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 800}, {SkyCover: "OVC", CloudBaseFtAgl: 1200}},
+			TempC:        -1, DewpointC: -3, AltimInHg: 29.92,
+		}
+		dir, _ := m.MagneticWindDir()
+		So(dir, ShouldEqual, 243)
+		runways, _ := ParseRunways("04L/22R 13L/31R", Declination(m, 4, m.ObservationTime))
+		a := NewATIS("Kennedy", runways)
+		a.MagneticWind = true
+		a.Remarks = []string{"bird activity in the vicinity"}
+		info := a.Update(m)
+		So(info.Runway, ShouldEqual, "22R")
+		So(info.Text, ShouldEqual, "Kennedy information Alfa, one two five one zulu. Runway in use two two right. "+
+			"Wind two four three at one four gusting two five knots. Visibility three quarters. Heavy rain showers, mist. "+
+			"Clouds broken eight hundred feet, overcast one thousand two hundred feet cumulonimbus. "+
+			"Temperature minus one, dew point minus three. Altimeter two niner niner two. "+
+			"Remarks, bird activity in the vicinity. Advise on initial contact you have information Alfa.")
+	})
+	Convey("The runway in use should not depend on the spoken wind reference", t, func() {
+		m := METAR{
+			RawText:         "KSEA 011253Z 14010KT 10SM FEW250 22/12 A3001 RMK AO2 NOSIG",
+			StationID:       "KSEA",
+			ObservationTime: time.Date(2019, 7, 1, 12, 53, 0, 0, time.UTC),
+			Latitude:        47.45, Longitude: -122.31,
+			WindDirDegrees: 140, WindSpeedKt: 10, VisibilityStatuteMi: 10, TempC: 22, DewpointC: 12, AltimInHg: 30.01,
+		}
+		runways, _ := ParseRunways("09/27 18/36", Declination(m, 130, m.ObservationTime))
+		a := NewATIS("Seattle", runways)
+		So(a.Update(m).Runway, ShouldEqual, "09")
+		a = NewATIS("Seattle", runways)
+		a.MagneticWind = true
+		info := a.Update(m)
+		So(info.Runway, ShouldEqual, "09")
+		So(info.Text, ShouldContainSubstring, "Wind one two four at one zero knots")
+		So(info.Text, ShouldNotContainSubstring, "No significant change")
+	})
+	Convey("Remarks should not change the units", t, func() {
+		m := METAR{
+			RawText:         "KBOS 011254Z 27010KT 10SM FEW250 22/12 A3001 RMK AO2 WSHFT 1215 QFE745",
+			StationID:       "KBOS",
+			ObservationTime: time.Date(2019, 7, 1, 12, 54, 0, 0, time.UTC),
+			WindDirDegrees:  270, WindSpeedKt: 10, VisibilityStatuteMi: 10, TempC: 22, DewpointC: 12, AltimInHg: 30.01,
+		}
+		text := NewATIS("Logan", nil).Update(m).Text
+		So(text, ShouldNotContainSubstring, "kilometres")
+		So(text, ShouldContainSubstring, "Visibility one zero.")
+		So(text, ShouldContainSubstring, "Altimeter three zero zero one.")
+	})
+	Convey("The letter should advance with every new report", t, func() {
+		a := NewATIS("", nil)
+		_, ok := a.Current()
+		So(ok, ShouldBeFalse)
+		So(a.Update(ulli).Letter, ShouldEqual, 'A')
+		So(a.Update(ulli).Letter, ShouldEqual, 'A')
+		next := ulli
+		next.ObservationTime = next.ObservationTime.Add(30 * time.Minute)
+		next.RawText = "ULLI 100830Z 23007MPS 9999 FEW040 20/11 Q1022"
+		info := a.Update(next)
+		So(info.Letter, ShouldEqual, 'B')
+		So(info.Text, ShouldStartWith, "ULLI information Bravo, zero eight three zero zulu. Wind")
+		current, _ := a.Current()
+		So(current.Letter, ShouldEqual, 'B')
+		So(a.SetLetter('Z'), ShouldBeNil)
+		next.ObservationTime = next.ObservationTime.Add(30 * time.Minute)
+		So(a.Update(next).Letter, ShouldEqual, 'Z')
+		next.ObservationTime = next.ObservationTime.Add(30 * time.Minute)
+		So(a.Update(next).Letter, ShouldEqual, 'A')
+		So(a.SetLetter('a'), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,238 @@
+package addstogo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Catalog is an indexed, read-only set of stations.
+type Catalog struct {
+	stations  []Station
+	byICAO    map[string]int
+	byWMO     map[string]int
+	byCountry map[string][]int
+	byState   map[string][]int
+}
+
+// StationFilter reports whether a station should be included in a result.
+type StationFilter func(s *Station) bool
+
+// NewCatalog indexes the stations. When a station ID repeats, the last one wins.
+func NewCatalog(stations []Station) *Catalog {
+	c := &Catalog{
+		byICAO:    make(map[string]int),
+		byWMO:     make(map[string]int),
+		byCountry: make(map[string][]int),
+		byState:   make(map[string][]int),
+	}
+	for _, s := range stations {
+		icao := strings.ToUpper(s.StationID)
+		if i, ok := c.byICAO[icao]; ok {
+			c.stations[i] = s
+			continue
+		}
+		c.byICAO[icao] = len(c.stations)
+		c.stations = append(c.stations, s)
+	}
+	for i, s := range c.stations {
+		if s.WMOID != "" {
+			c.byWMO[s.WMOID] = i
+		}
+		country := strings.ToUpper(s.Country)
+		c.byCountry[country] = append(c.byCountry[country], i)
+		if s.State != "" {
+			state := country + "/" + strings.ToUpper(s.State)
+			c.byState[state] = append(c.byState[state], i)
+		}
+	}
+	return c
+}
+
+// LoadCatalog reads a station XML response or the stations cache file, plain or gzipped.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	si, err := UnmarshalStationsInfo(input)
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalog(si.Data.Station), nil
+}
+
+// Len returns the number of stations in the catalog.
+func (c *Catalog) Len() int {
+	return len(c.stations)
+}
+
+// Stations returns all stations matching every filter.
+func (c *Catalog) Stations(filters ...StationFilter) []Station {
+	var result []Station
+	for i := range c.stations {
+		if matchAll(&c.stations[i], filters) {
+			result = append(result, c.stations[i])
+		}
+	}
+	return result
+}
+
+// Station returns the station with the given ICAO identifier, ignoring case.
+func (c *Catalog) Station(icao string) (Station, bool) {
+	i, ok := c.byICAO[strings.ToUpper(strings.TrimSpace(icao))]
+	if !ok {
+		return Station{}, false
+	}
+	return c.stations[i], true
+}
+
+// StationByWMO returns the station with the given WMO index number.
+func (c *Catalog) StationByWMO(wmoID string) (Station, bool) {
+	i, ok := c.byWMO[strings.TrimSpace(wmoID)]
+	if !ok {
+		return Station{}, false
+	}
+	return c.stations[i], true
+}
+
+// Country returns the stations of the country matching every filter.
+func (c *Catalog) Country(country string, filters ...StationFilter) []Station {
+	return c.collect(c.byCountry[strings.ToUpper(country)], filters)
+}
+
+// State returns the stations of the state or province of the country matching every filter.
+func (c *Catalog) State(country, state string, filters ...StationFilter) []Station {
+	return c.collect(c.byState[strings.ToUpper(country)+"/"+strings.ToUpper(state)], filters)
+}
+
+// Search returns the stations whose site name contains the query, ignoring case.
+func (c *Catalog) Search(query string, filters ...StationFilter) []Station {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	return c.Stations(append([]StationFilter{func(s *Station) bool {
+		return strings.Contains(strings.ToUpper(s.Site), query)
+	}}, filters...)...)
+}
+
+// FuzzySearch returns the stations whose site name contains the query with at most
+// maxDistance typing errors, best matches first.
+func (c *Catalog) FuzzySearch(query string, maxDistance int, filters ...StationFilter) []Station {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	type match struct {
+		index, distance int
+	}
+	var matches []match
+	for i := range c.stations {
+		s := &c.stations[i]
+		if !matchAll(s, filters) {
+			continue
+		}
+		if d := substringDistance(query, strings.ToUpper(s.Site)); d <= maxDistance {
+			matches = append(matches, match{i, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+	result := make([]Station, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, c.stations[m.index])
+	}
+	return result
+}
+
+func (c *Catalog) collect(indexes []int, filters []StationFilter) []Station {
+	var result []Station
+	for _, i := range indexes {
+		if matchAll(&c.stations[i], filters) {
+			result = append(result, c.stations[i])
+		}
+	}
+	return result
+}
+
+func matchAll(s *Station, filters []StationFilter) bool {
+	for _, f := range filters {
+		if !f(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// substringDistance is the smallest Levenshtein distance between the pattern
+// and any substring of the text.
+func substringDistance(pattern, text string) int {
+	p, t := []rune(pattern), []rune(text)
+	prev := make([]int, len(p)+1)
+	cur := make([]int, len(p)+1)
+	for i := range prev {
+		prev[i] = i
+	}
+	best := prev[len(p)]
+	for j := 1; j <= len(t); j++ {
+		cur[0] = 0
+		for i := 1; i <= len(p); i++ {
+			cost := 1
+			if p[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[i] = minInt(prev[i-1]+cost, minInt(prev[i]+1, cur[i-1]+1))
+		}
+		if cur[len(p)] < best {
+			best = cur[len(p)]
+		}
+		prev, cur = cur, prev
+	}
+	return best
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// HasMETAR selects the stations reporting METAR.
+func HasMETAR(s *Station) bool { return s.SiteType.METAR }
+
+// HasTAF selects the stations issuing TAF.
+func HasTAF(s *Station) bool { return s.SiteType.TAF }
+
+// HasNEXRAD selects the weather radar sites.
+func HasNEXRAD(s *Station) bool { return s.SiteType.NEXRAD }
+
+// HasRawinsonde selects the upper air sounding sites.
+func HasRawinsonde(s *Station) bool { return s.SiteType.Rawinsonde }
+
+// HasWindProfiler selects the wind profiler sites.
+func HasWindProfiler(s *Station) bool { return s.SiteType.WindProfiler }
+
+// IsWFOoffice selects the weather forecast offices.
+func IsWFOoffice(s *Station) bool { return s.SiteType.WFOoffice }
+
+// InCountry selects the stations of the country.
+func InCountry(country string) StationFilter {
+	return func(s *Station) bool { return strings.EqualFold(s.Country, country) }
+}
+
+// InState selects the stations of the state or province.
+func InState(state string) StationFilter {
+	return func(s *Station) bool { return strings.EqualFold(s.State, state) }
+}
@@ -0,0 +1,73 @@
+package addstogo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const stationsFixture = `<response xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XML-Schema-instance" version="1.0" xsi:noNamespaceSchemaLocation="http://weather.aero/schema/station1_0.xsd"><request_index>84789653</request_index><data_source name="stations"/><request type="retrieve"/><errors/><warnings/><time_taken_ms>5</time_taken_ms><data num_results="3"><Station><station_id>KDEN</station_id><wmo_id>72565</wmo_id><latitude>39.85</latitude><longitude>-104.65</longitude><elevation_m>1640.0</elevation_m><site>DENVER (DIA)</site><state>CO</state><country>US</country><site_type><METAR/></site_type></Station><Station><station_id>KSEA</station_id><wmo_id>72793</wmo_id><latitude>47.45</latitude><longitude>-122.32</longitude><elevation_m>136.0</elevation_m><site>SEATTLE/METRO</site><state>WA</state><country>US</country><site_type><METAR/><TAF/></site_type></Station><Station><station_id>PHNL</station_id><wmo_id>91182</wmo_id><latitude>21.33</latitude><longitude>-157.92</longitude><elevation_m>4.0</elevation_m><site>HONOLULU</site><state>HI</state><country>US</country><site_type><METAR/><TAF/></site_type></Station><Station><station_id>KABR</station_id><wmo_id>72659</wmo_id><latitude>45.45</latitude><longitude>-98.42</longitude><elevation_m>397.0</elevation_m><site>ABERDEEN</site><state>SD</state><country>US</country><site_type><METAR/><NEXRAD/><rawinsonde/><WFO_office/><TAF/></site_type></Station></data></response>`
+
+func stationIDs(stations []Station) []string {
+	ids := make([]string, 0, len(stations))
+	for _, s := range stations {
+		ids = append(ids, s.StationID)
+	}
+	return ids
+}
+
+func TestCatalog(t *testing.T) {
+	Convey("Catalog loaded from station XML", t, func() {
+		c, err := LoadCatalog(strings.NewReader(stationsFixture))
+		So(err, ShouldBeNil)
+		So(c.Len(), ShouldEqual, 4)
+
+		Convey("should find stations by ICAO and WMO identifiers", func() {
+			s, ok := c.Station("ksea")
+			So(ok, ShouldBeTrue)
+			So(s.Site, ShouldEqual, "SEATTLE/METRO")
+			s, ok = c.StationByWMO("91182")
+			So(ok, ShouldBeTrue)
+			So(s.StationID, ShouldEqual, "PHNL")
+			_, ok = c.Station("ULLI")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("should list stations by country and state", func() {
+			So(stationIDs(c.Country("us")), ShouldResemble, []string{"KDEN", "KSEA", "PHNL", "KABR"})
+			So(stationIDs(c.Country("US", HasTAF)), ShouldResemble, []string{"KSEA", "PHNL", "KABR"})
+			So(stationIDs(c.State("US", "wa")), ShouldResemble, []string{"KSEA"})
+			So(c.Country("RU"), ShouldBeEmpty)
+		})
+		Convey("should search site names ignoring case", func() {
+			So(stationIDs(c.Search("honolulu")), ShouldResemble, []string{"PHNL"})
+			So(stationIDs(c.Search("E", HasNEXRAD)), ShouldResemble, []string{"KABR"})
+		})
+		Convey("should tolerate typing errors in fuzzy search", func() {
+			So(stationIDs(c.FuzzySearch("HONOLULO", 1)), ShouldResemble, []string{"PHNL"})
+			So(stationIDs(c.FuzzySearch("SEATLE", 1)), ShouldResemble, []string{"KSEA"})
+			So(c.FuzzySearch("SEATLE", 0), ShouldBeEmpty)
+		})
+		Convey("should filter by capabilities", func() {
+			So(stationIDs(c.Stations(InCountry("US"), HasTAF, HasRawinsonde)), ShouldResemble, []string{"KABR"})
+			So(stationIDs(c.Stations(InState("co"))), ShouldResemble, []string{"KDEN"})
+		})
+	})
+	Convey("Catalog should be loaded from a gzipped cache file", t, func() {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(stationsFixture))
+		gz.Close()
+		c, err := LoadCatalog(&buf)
+		So(err, ShouldBeNil)
+		So(c.Len(), ShouldEqual, 4)
+	})
+	Convey("Repeated stations should replace the earlier ones", t, func() {
+		c := NewCatalog([]Station{{StationID: "KSEA", Site: "OLD"}, {StationID: "KSEA", Site: "NEW"}})
+		So(c.Len(), ShouldEqual, 1)
+		s, _ := c.Station("KSEA")
+		So(s.Site, ShouldEqual, "NEW")
+	})
+}
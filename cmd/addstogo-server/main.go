@@ -0,0 +1,132 @@
+// Command addstogo-server serves decoded METARs, TAFs and stations as JSON over HTTP,
+// streams the polled reports as Server-Sent Events on /events and exposes Prometheus metrics on /metrics.
+//
+// Reports are polled from a URL or re-read from a cache file into memory, or served
+// from an archive directory written by addstogo.Archive.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urkk/addstogo"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	metars := flag.String("metars", "", "METAR URL or cache file")
+	tafs := flag.String("tafs", "", "TAF URL or cache file")
+	stations := flag.String("stations", "", "station XML file, plain or gzipped")
+	archive := flag.String("archive", "", "archive directory to serve when no METAR source is given, or to write to")
+	interval := flag.Duration("interval", 5*time.Minute, "polling interval")
+	flag.Parse()
+	if *interval <= 0 {
+		log.Fatal("-interval must be positive")
+	}
+
+	var catalog *addstogo.Catalog
+	if *stations != "" {
+		f, err := os.Open(*stations)
+		if err != nil {
+			log.Fatal(err)
+		}
+		catalog, err = addstogo.LoadCatalog(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var a *addstogo.Archive
+	if *archive != "" {
+		var err error
+		if a, err = addstogo.OpenArchive(*archive); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	store := addstogo.NewStore()
+	events := addstogo.NewBroadcaster(1000)
+	metrics := addstogo.NewMetrics(store)
+	var source addstogo.WeatherSource
+	switch {
+	case *metars != "" || *tafs != "":
+		// the watcher keeps its own store to tell new reports apart, the served one keeps the history
+		w := addstogo.NewWatcher(nil, *interval)
+		w.OnError = func(err error) { log.Print(err) }
+		client := &http.Client{Timeout: *interval}
+		if *metars != "" {
+			w.Source = addstogo.FileSource(*metars)
+			if isURL(*metars) {
+				w.Source = addstogo.URLSource(*metars, client)
+			}
+			w.Source = metrics.InstrumentMETARSource("metars", w.Source)
+		}
+		if *tafs != "" {
+			w.TAFSource = addstogo.TAFFileSource(*tafs)
+			if isURL(*tafs) {
+				w.TAFSource = addstogo.TAFURLSource(*tafs, client)
+			}
+			w.TAFSource = metrics.InstrumentTAFSource("tafs", w.TAFSource)
+		}
+		go poll(w, store, a, events)
+		source = addstogo.StoreSource(store)
+	case a != nil:
+		source = addstogo.ArchiveSource(a)
+	default:
+		log.Fatal("one of -metars, -tafs or -archive is required")
+	}
+
+	log.Printf("listening on %s", *addr)
+	mux := http.NewServeMux()
+	mux.Handle("/events", events)
+	mux.Handle("/metrics", metrics)
+	mux.Handle("/", addstogo.NewServer(source, catalog))
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// poll refreshes the store, and the archive when given, every interval and publishes the fresh reports.
+// The store keeps the last 48 hours.
+func poll(w *addstogo.Watcher, store *addstogo.Store, a *addstogo.Archive, events *addstogo.Broadcaster) {
+	w.RunReports(context.Background(), func(changes []addstogo.METARChange, tafs []addstogo.TAF) {
+		metars := make([]addstogo.METAR, 0, len(changes))
+		for _, c := range changes {
+			metars = append(metars, c.METAR)
+			store.AddMETAR(c.METAR)
+			events.PublishMETAR(c.METAR)
+		}
+		for _, t := range tafs {
+			store.AddTAF(t)
+			events.PublishTAF(t)
+		}
+		store.Prune(time.Now().Add(-48 * time.Hour))
+		if w.Source != nil {
+			log.Printf("metars: %d new or corrected", len(metars))
+		}
+		if w.TAFSource != nil {
+			log.Printf("tafs: %d new or amended", len(tafs))
+		}
+		if a == nil {
+			return
+		}
+		if len(metars) > 0 {
+			if err := a.WriteMETARs(metars); err != nil {
+				log.Printf("archive: %v", err)
+			}
+		}
+		if len(tafs) > 0 {
+			if err := a.WriteTAFs(tafs); err != nil {
+				log.Printf("archive: %v", err)
+			}
+		}
+	})
+}
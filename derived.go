@@ -0,0 +1,120 @@
+package addstogo
+
+import "math"
+
+// The derived quantities return false when an input was not reported, see groupsOf.
+// ElevationM is taken as reported, as zero is the elevation of many coastal stations.
+
+// saturationVapourPressure returns the saturation vapour pressure over water in hPa (Magnus formula).
+func saturationVapourPressure(tempC float64) float64 {
+	return 6.1094 * math.Exp(17.625*tempC/(tempC+243.04))
+}
+
+// RelativeHumidity returns the relative humidity in percent.
+func (m METAR) RelativeHumidity() (float64, bool) {
+	g := groupsOf(&m)
+	if !g.temperature || !g.dewpoint {
+		return 0, false
+	}
+	return 100 * saturationVapourPressure(decimal(m.DewpointC)) / saturationVapourPressure(decimal(m.TempC)), true
+}
+
+// DewpointSpread returns the difference between the temperature and the dew point in °C.
+func (m METAR) DewpointSpread() (float64, bool) {
+	g := groupsOf(&m)
+	if !g.temperature || !g.dewpoint {
+		return 0, false
+	}
+	return decimal(m.TempC) - decimal(m.DewpointC), true
+}
+
+// PressureAltitudeFt returns the pressure altitude of the station in feet.
+func (m METAR) PressureAltitudeFt() (float64, bool) {
+	if !groupsOf(&m).altimeter {
+		return 0, false
+	}
+	qfe := m.qfe()
+	return 145366.45 * (1 - math.Pow(qfe/1013.25, 0.190284)), true
+}
+
+// DensityAltitudeFt returns the density altitude of the station in feet,
+// using the rule of 120 ft per °C of deviation from the standard atmosphere.
+func (m METAR) DensityAltitudeFt() (float64, bool) {
+	pa, ok := m.PressureAltitudeFt()
+	if !ok || !groupsOf(&m).temperature {
+		return 0, false
+	}
+	isa := 15 - 1.98*pa/1000
+	return pa + 120*(decimal(m.TempC)-isa), true
+}
+
+// qfe converts the altimeter setting to the station pressure in hPa.
+func (m *METAR) qfe() float64 {
+	qnh := float64(m.AltimInHg) / inHgPerHPa
+	return math.Pow(math.Pow(qnh, 0.190263)-8.417286e-5*float64(m.ElevationM), 1/0.190263)
+}
+
+// QFEMb returns the station pressure in hPa derived from the altimeter setting and the elevation.
+func (m METAR) QFEMb() (float64, bool) {
+	if !groupsOf(&m).altimeter {
+		return 0, false
+	}
+	return m.qfe(), true
+}
+
+// HeatIndexC returns the apparent temperature in hot humid air in °C, computed as the US National Weather Service does.
+func (m METAR) HeatIndexC() (float64, bool) {
+	rh, ok := m.RelativeHumidity()
+	if !ok {
+		return 0, false
+	}
+	t := decimal(m.TempC)*9/5 + 32
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+			0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return (hi - 32) * 5 / 9, true
+}
+
+// WindChillC returns the wind chill temperature in °C. It equals the air temperature above 10 °C
+// or in winds under 3 kt, where the index is not defined.
+func (m METAR) WindChillC() (float64, bool) {
+	g := groupsOf(&m)
+	if !g.temperature || !g.wind {
+		return 0, false
+	}
+	t := decimal(m.TempC)
+	v := float64(m.WindSpeedKt) * 1.852
+	if t > 10 || v < 4.8 {
+		return t, true
+	}
+	p := math.Pow(v, 0.16)
+	return 13.12 + 0.6215*t - 11.37*p + 0.3965*t*p, true
+}
+
+// Humidex returns the Canadian humidity index.
+func (m METAR) Humidex() (float64, bool) {
+	g := groupsOf(&m)
+	if !g.temperature || !g.dewpoint {
+		return 0, false
+	}
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+decimal(m.DewpointC))))
+	return decimal(m.TempC) + 0.5555*(e-10), true
+}
+
+// CumulusBaseFtAgl estimates the base of convective clouds above the ground in feet,
+// from the dew point spread at 400 ft per °C.
+func (m METAR) CumulusBaseFtAgl() (float64, bool) {
+	spread, ok := m.DewpointSpread()
+	if !ok {
+		return 0, false
+	}
+	return math.Max(0, spread*400), true
+}
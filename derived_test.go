@@ -0,0 +1,69 @@
+package addstogo
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDerived(t *testing.T) {
+	Convey("Derived quantities of a METAR", t, func() {
+		ulli := METAR{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG", TempC: 20, DewpointC: 11, WindSpeedKt: 14, AltimInHg: 30.177166, ElevationM: 4}
+		kden := METAR{RawText: "KDEN 072153Z 17008KT 10SM FEW120 32/05 A3012", TempC: 32, DewpointC: 5, WindSpeedKt: 8, AltimInHg: 30.12, ElevationM: 1640}
+
+		Convey("should compute humidity and spread", func() {
+			rh, ok := ulli.RelativeHumidity()
+			So(ok, ShouldBeTrue)
+			So(rh, ShouldAlmostEqual, 56.16, 0.01)
+			spread, _ := ulli.DewpointSpread()
+			So(spread, ShouldEqual, 9)
+			base, _ := ulli.CumulusBaseFtAgl()
+			So(base, ShouldEqual, 3600)
+		})
+		Convey("should compute pressures and altitudes", func() {
+			qfe, ok := kden.QFEMb()
+			So(ok, ShouldBeTrue)
+			So(qfe, ShouldAlmostEqual, 836.94, 0.01)
+			pa, _ := kden.PressureAltitudeFt()
+			So(pa, ShouldAlmostEqual, 5192.9, 0.1)
+			da, _ := kden.DensityAltitudeFt()
+			So(da, ShouldAlmostEqual, 8466.7, 0.1)
+			qfe, _ = ulli.QFEMb()
+			So(qfe, ShouldAlmostEqual, 1021.5, 0.1)
+		})
+		Convey("should compute apparent temperatures", func() {
+			hot := METAR{RawText: "OMDB 071200Z 32010KT CAVOK 35/25 Q1003", TempC: 35, DewpointC: 25, WindSpeedKt: 10}
+			hi, ok := hot.HeatIndexC()
+			So(ok, ShouldBeTrue)
+			So(hi, ShouldAlmostEqual, 43.32, 0.01)
+			hx, _ := hot.Humidex()
+			So(hx, ShouldAlmostEqual, 47.34, 0.01)
+			cold := METAR{RawText: "CYUL 071200Z 27020KT 15SM M10/M16 A3020", TempC: -10, DewpointC: -16, WindSpeedKt: 20}
+			wc, ok := cold.WindChillC()
+			So(ok, ShouldBeTrue)
+			So(wc, ShouldAlmostEqual, -20.43, 0.01)
+			wc, _ = ulli.WindChillC()
+			So(wc, ShouldEqual, 20)
+			hi, _ = cold.HeatIndexC()
+			So(hi, ShouldBeLessThan, -10)
+		})
+		Convey("should be unknown when an input is missing", func() {
+			missing := METAR{RawText: "KBTV 071554Z AUTO 00000KT 1/2SM FZFG VV002 M05/ A2992", TempC: -5, AltimInHg: 29.92}
+			_, ok := missing.RelativeHumidity()
+			So(ok, ShouldBeFalse)
+			_, ok = missing.Humidex()
+			So(ok, ShouldBeFalse)
+			_, ok = missing.CumulusBaseFtAgl()
+			So(ok, ShouldBeFalse)
+			_, ok = missing.DensityAltitudeFt()
+			So(ok, ShouldBeTrue)
+			noAltimeter := METAR{RawText: "UUEE 071200Z 27005MPS 9999 BKN020 15/10", TempC: 15, DewpointC: 10}
+			_, ok = noAltimeter.PressureAltitudeFt()
+			So(ok, ShouldBeFalse)
+			_, ok = noAltimeter.QFEMb()
+			So(ok, ShouldBeFalse)
+			_, ok = METAR{RawText: "UUEE 071200Z NIL"}.WindChillC()
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,94 @@
+// Package geodesy provides great-circle calculations on a spherical Earth
+// for the station coordinates found in METAR, TAF and station records.
+package geodesy
+
+import "math"
+
+const (
+	// EarthRadiusKm is the mean radius of the Earth.
+	EarthRadiusKm = 6371.0088
+	// KmPerNM is the length of the international nautical mile.
+	KmPerNM = 1.852
+	// EarthRadiusNM is the mean radius of the Earth in nautical miles.
+	EarthRadiusNM = EarthRadiusKm / KmPerNM
+)
+
+// Point is a position in decimal degrees, north and east positive.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+// normalizeBearing brings a bearing into [0, 360).
+func normalizeBearing(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// normalizeLongitude brings a longitude into [-180, 180).
+func normalizeLongitude(deg float64) float64 {
+	return math.Mod(deg+540, 360) - 180
+}
+
+// Angle returns the central angle between the points in radians, by the haversine formula.
+func Angle(a, b Point) float64 {
+	phi1, phi2 := radians(a.Latitude), radians(b.Latitude)
+	dPhi, dLambda := phi2-phi1, radians(b.Longitude-a.Longitude)
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// DistanceNM returns the great-circle distance in nautical miles.
+func DistanceNM(a, b Point) float64 {
+	return Angle(a, b) * EarthRadiusNM
+}
+
+// DistanceKm returns the great-circle distance in kilometres.
+func DistanceKm(a, b Point) float64 {
+	return Angle(a, b) * EarthRadiusKm
+}
+
+// InitialBearing returns the true course in degrees when leaving a towards b.
+func InitialBearing(a, b Point) float64 {
+	phi1, phi2 := radians(a.Latitude), radians(b.Latitude)
+	dLambda := radians(b.Longitude - a.Longitude)
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// FinalBearing returns the true course in degrees when arriving at b from a.
+func FinalBearing(a, b Point) float64 {
+	return normalizeBearing(InitialBearing(b, a) + 180)
+}
+
+// Midpoint returns the point halfway along the great circle between a and b.
+func Midpoint(a, b Point) Point {
+	phi1, phi2 := radians(a.Latitude), radians(b.Latitude)
+	lambda1, dLambda := radians(a.Longitude), radians(b.Longitude-a.Longitude)
+	bx, by := math.Cos(phi2)*math.Cos(dLambda), math.Cos(phi2)*math.Sin(dLambda)
+	phi := math.Atan2(math.Sin(phi1)+math.Sin(phi2), math.Sqrt((math.Cos(phi1)+bx)*(math.Cos(phi1)+bx)+by*by))
+	lambda := lambda1 + math.Atan2(by, math.Cos(phi1)+bx)
+	return Point{Latitude: degrees(phi), Longitude: normalizeLongitude(degrees(lambda))}
+}
+
+// Destination returns the point reached from a after distanceNM on the true initial bearing.
+func Destination(a Point, bearing, distanceNM float64) Point {
+	phi1, lambda1 := radians(a.Latitude), radians(a.Longitude)
+	theta, delta := radians(bearing), distanceNM/EarthRadiusNM
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+	return Point{Latitude: degrees(phi2), Longitude: normalizeLongitude(degrees(lambda2))}
+}
+
+// DestinationKm is Destination with the distance in kilometres.
+func DestinationKm(a Point, bearing, distanceKm float64) Point {
+	return Destination(a, bearing, distanceKm/KmPerNM)
+}
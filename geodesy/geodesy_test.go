@@ -0,0 +1,56 @@
+package geodesy
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGeodesy(t *testing.T) {
+	lax := Point{Latitude: 33.95, Longitude: -118.4}
+	jfk := Point{Latitude: 40.6333, Longitude: -73.7833}
+
+	Convey("Distance should follow the great circle", t, func() {
+		So(DistanceNM(lax, jfk), ShouldAlmostEqual, 2145, 1)
+		So(DistanceKm(lax, jfk), ShouldAlmostEqual, 3973, 1)
+		So(DistanceNM(lax, lax), ShouldEqual, 0)
+		So(DistanceNM(Point{0, 0}, Point{0, 180}), ShouldAlmostEqual, 10808, 1)
+	})
+	Convey("Bearings should be true degrees", t, func() {
+		So(InitialBearing(lax, jfk), ShouldAlmostEqual, 65.9, 0.1)
+		So(FinalBearing(lax, jfk), ShouldAlmostEqual, 93.8, 0.1)
+		So(InitialBearing(Point{35, 45}, Point{35, 135}), ShouldAlmostEqual, 60.2, 0.1)
+		So(FinalBearing(Point{35, 45}, Point{35, 135}), ShouldAlmostEqual, 119.8, 0.1)
+		So(InitialBearing(Point{10, 10}, Point{0, 10}), ShouldAlmostEqual, 180, 1e-9)
+	})
+	Convey("Midpoint should be equidistant", t, func() {
+		m := Midpoint(lax, jfk)
+		So(DistanceNM(lax, m), ShouldAlmostEqual, DistanceNM(m, jfk), 1e-6)
+		So(m.Latitude, ShouldAlmostEqual, 39.5, 0.1)
+		So(m.Longitude, ShouldAlmostEqual, -97.2, 0.1)
+		So(Midpoint(Point{0, 170}, Point{0, -170}).Longitude, ShouldAlmostEqual, -180, 1e-9)
+	})
+	Convey("Destination should invert distance and bearing", t, func() {
+		d := Destination(lax, InitialBearing(lax, jfk), DistanceNM(lax, jfk))
+		So(d.Latitude, ShouldAlmostEqual, jfk.Latitude, 1e-9)
+		So(d.Longitude, ShouldAlmostEqual, jfk.Longitude, 1e-9)
+		e := DestinationKm(Point{0, 179.5}, 90, 111.19508)
+		So(e.Latitude, ShouldAlmostEqual, 0, 1e-9)
+		So(e.Longitude, ShouldAlmostEqual, -179.5, 1e-6)
+	})
+	Convey("Vectors should follow the great circle", t, func() {
+		p := lax.Vector().Point()
+		So(p.Latitude, ShouldAlmostEqual, lax.Latitude, 1e-9)
+		So(p.Longitude, ShouldAlmostEqual, lax.Longitude, 1e-9)
+		m := Interpolate(lax, jfk, 0.5)
+		So(m.Latitude, ShouldAlmostEqual, Midpoint(lax, jfk).Latitude, 1e-9)
+		So(m.Longitude, ShouldAlmostEqual, Midpoint(lax, jfk).Longitude, 1e-9)
+		So(Interpolate(Point{0, 0}, Point{0, 170}, 0.5).Longitude, ShouldAlmostEqual, 85, 1e-9)
+		crossTrack, alongTrack := SegmentDistance(Point{0, 0}, Point{0, 90}, Point{1, 30})
+		So(crossTrack, ShouldAlmostEqual, Angle(Point{0, 30}, Point{1, 30}), 1e-9)
+		So(alongTrack, ShouldAlmostEqual, Angle(Point{0, 0}, Point{0, 30}), 1e-9)
+		crossTrack, alongTrack = SegmentDistance(Point{0, 0}, Point{0, 90}, Point{0, 100})
+		So(crossTrack, ShouldAlmostEqual, Angle(Point{0, 90}, Point{0, 100}), 1e-9)
+		So(alongTrack, ShouldAlmostEqual, Angle(Point{0, 0}, Point{0, 90}), 1e-9)
+	})
+}
@@ -0,0 +1,74 @@
+package geodesy
+
+import "math"
+
+// Vector is a position as a unit vector from the centre of the Earth,
+// x towards 0°N 0°E, y towards 0°N 90°E and z towards the North Pole.
+type Vector [3]float64
+
+// Vector returns the position as a unit vector.
+func (p Point) Vector() Vector {
+	phi, lambda := radians(p.Latitude), radians(p.Longitude)
+	return Vector{math.Cos(phi) * math.Cos(lambda), math.Cos(phi) * math.Sin(lambda), math.Sin(phi)}
+}
+
+// Point returns the position the vector points to. The vector need not be of unit length.
+func (v Vector) Point() Point {
+	return Point{Latitude: degrees(math.Atan2(v[2], math.Hypot(v[0], v[1]))), Longitude: degrees(math.Atan2(v[1], v[0]))}
+}
+
+func (v Vector) dot(w Vector) float64 {
+	return v[0]*w[0] + v[1]*w[1] + v[2]*w[2]
+}
+
+func (v Vector) cross(w Vector) Vector {
+	return Vector{v[1]*w[2] - v[2]*w[1], v[2]*w[0] - v[0]*w[2], v[0]*w[1] - v[1]*w[0]}
+}
+
+func (v Vector) normalize() Vector {
+	n := math.Sqrt(v.dot(v))
+	if n == 0 {
+		return v
+	}
+	return Vector{v[0] / n, v[1] / n, v[2] / n}
+}
+
+// angle returns the angle in radians between two unit vectors, accurate also for nearly antipodal ones.
+func (v Vector) angle(w Vector) float64 {
+	c := v.cross(w)
+	return math.Atan2(math.Sqrt(c.dot(c)), v.dot(w))
+}
+
+// Interpolate returns the point at the fraction of the great-circle arc from a to b.
+// The arc between antipodal points is undefined.
+func Interpolate(a, b Point, fraction float64) Point {
+	va, vb := a.Vector(), b.Vector()
+	length := va.angle(vb)
+	if length == 0 {
+		return a
+	}
+	wa, wb := math.Sin((1-fraction)*length)/math.Sin(length), math.Sin(fraction*length)/math.Sin(length)
+	return Vector{wa*va[0] + wb*vb[0], wa*va[1] + wb*vb[1], wa*va[2] + wb*vb[2]}.Point()
+}
+
+// SegmentDistance returns the central angle in radians from p to the great-circle segment a-b
+// and the central angle from a to the point of the segment closest to p.
+func SegmentDistance(a, b, p Point) (crossTrack, alongTrack float64) {
+	va, vb, vp := a.Vector(), b.Vector(), p.Vector()
+	length := va.angle(vb)
+	n := va.cross(vb)
+	if n.dot(n) < 1e-24 {
+		return va.angle(vp), 0
+	}
+	n = n.normalize()
+	d := vp.dot(n)
+	projected := Vector{vp[0] - d*n[0], vp[1] - d*n[1], vp[2] - d*n[2]}.normalize()
+	along := math.Atan2(va.cross(projected).dot(n), va.dot(projected))
+	switch {
+	case along < 0:
+		return va.angle(vp), 0
+	case along > length:
+		return vb.angle(vp), length
+	}
+	return math.Abs(math.Asin(math.Max(-1, math.Min(1, d)))), along
+}
@@ -0,0 +1,163 @@
+package addstogo
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is a time-series sample. Field values are float64 or int64.
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// METARPoint returns the observation as a point of the measurement "metar" tagged by station and metar_type,
+// with the numeric fields that were reported. It returns false when none was.
+func METARPoint(m METAR) (Point, bool) {
+	p := Point{
+		Measurement: "metar",
+		Tags:        map[string]string{"station": m.StationID},
+		Fields:      make(map[string]interface{}),
+		Time:        m.ObservationTime,
+	}
+	if m.MetarType != "" {
+		p.Tags["metar_type"] = m.MetarType
+	}
+	g := groupsOf(&m)
+	if g.wind {
+		p.Fields["wind_dir_degrees"] = int64(m.WindDirDegrees)
+		p.Fields["wind_speed_kt"] = int64(m.WindSpeedKt)
+	}
+	if g.gust {
+		p.Fields["wind_gust_kt"] = int64(m.WindGustKt)
+	}
+	if g.visibility {
+		p.Fields["visibility_statute_mi"] = decimal(m.VisibilityStatuteMi)
+	}
+	if g.temperature {
+		p.Fields["temp_c"] = decimal(m.TempC)
+	}
+	if g.dewpoint {
+		p.Fields["dewpoint_c"] = decimal(m.DewpointC)
+	}
+	if g.altimeter {
+		p.Fields["altim_in_hg"] = decimal(m.AltimInHg)
+	}
+	if g.seaLevelPressure {
+		p.Fields["sea_level_pressure_mb"] = decimal(m.SeaLevelPressureMb)
+	}
+	if c, ok := ceilingFt(&m); ok {
+		p.Fields["ceiling_ft_agl"] = int64(c)
+	}
+	if m.VertVisFt != 0 {
+		p.Fields["vert_vis_ft"] = int64(m.VertVisFt)
+	}
+	return p, len(p.Fields) > 0
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LineProtocol renders the point in the InfluxDB line protocol with a nanosecond timestamp.
+// Tags and fields are sorted by key, empty tags are left out.
+func (p Point) LineProtocol() string {
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+	for _, k := range sortedKeys(p.Tags) {
+		if p.Tags[k] == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(tagEscaper.Replace(k))
+		b.WriteByte('=')
+		b.WriteString(tagEscaper.Replace(p.Tags[k]))
+	}
+	fields := make([]string, 0, len(p.Fields))
+	for k := range p.Fields {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	for i, k := range fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(tagEscaper.Replace(k))
+		b.WriteByte('=')
+		switch v := p.Fields[k].(type) {
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10))
+			b.WriteByte('i')
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+	return b.String()
+}
+
+// LineProtocolWriter writes points in the InfluxDB line protocol, flushing every batch of lines.
+type LineProtocolWriter struct {
+	w         *bufio.Writer
+	batchSize int
+	pending   int
+}
+
+// NewLineProtocolWriter returns a writer flushing to w every batchSize lines. Zero means 5000.
+func NewLineProtocolWriter(w io.Writer, batchSize int) *LineProtocolWriter {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	return &LineProtocolWriter{w: bufio.NewWriter(w), batchSize: batchSize}
+}
+
+// WritePoint writes one line.
+func (lw *LineProtocolWriter) WritePoint(p Point) error {
+	if _, err := lw.w.WriteString(p.LineProtocol()); err != nil {
+		return err
+	}
+	if err := lw.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	if lw.pending++; lw.pending >= lw.batchSize {
+		return lw.Flush()
+	}
+	return nil
+}
+
+// WriteMETARs writes every observation of the response with at least one reported value.
+func (lw *LineProtocolWriter) WriteMETARs(r *METARresponse) error {
+	for _, m := range r.Data.METAR {
+		if p, ok := METARPoint(m); ok {
+			if err := lw.WritePoint(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flush writes the pending lines to the underlying writer.
+func (lw *LineProtocolWriter) Flush() error {
+	lw.pending = 0
+	return lw.w.Flush()
+}
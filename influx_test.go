@@ -0,0 +1,47 @@
+package addstogo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLineProtocol(t *testing.T) {
+	ulli := METAR{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG", StationID: "ULLI",
+		ObservationTime: time.Date(2019, 6, 10, 8, 0, 0, 0, time.UTC), TempC: 20, DewpointC: 11, WindDirDegrees: 230, WindSpeedKt: 14,
+		VisibilityStatuteMi: 6.21, AltimInHg: 30.177166, SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}}, MetarType: "METAR"}
+	Convey("METAR points should hold only the reported values", t, func() {
+		p, ok := METARPoint(ulli)
+		So(ok, ShouldBeTrue)
+		So(p.LineProtocol(), ShouldEqual, "metar,metar_type=METAR,station=ULLI altim_in_hg=30.177166,dewpoint_c=11,temp_c=20,"+
+			"visibility_statute_mi=6.21,wind_dir_degrees=230i,wind_speed_kt=14i 1560153600000000000")
+
+		m := METAR{RawText: "KBTV 071554Z AUTO 00000KT 1/2SM FZFG VV002 M05/ A2992 RMK AO2 SLP142", StationID: "KBTV",
+			ObservationTime: ulli.ObservationTime, TempC: -5, AltimInHg: 29.92, SeaLevelPressureMb: 1014.2, VisibilityStatuteMi: 0.5, VertVisFt: 200, MetarType: "SPECI"}
+		p, _ = METARPoint(m)
+		So(p.Fields, ShouldResemble, map[string]interface{}{
+			"wind_dir_degrees": int64(0), "wind_speed_kt": int64(0), "visibility_statute_mi": 0.5, "temp_c": -5.0,
+			"altim_in_hg": 29.92, "sea_level_pressure_mb": 1014.2, "ceiling_ft_agl": int64(200), "vert_vis_ft": int64(200)})
+
+		p, _ = METARPoint(METAR{StationID: "ZZZZ", TempC: 3, WindGustKt: 25})
+		So(p.Fields, ShouldResemble, map[string]interface{}{"temp_c": 3.0, "wind_gust_kt": int64(25)})
+		_, ok = METARPoint(METAR{StationID: "ZZZZ", RawText: "ZZZZ 071554Z NIL"})
+		So(ok, ShouldBeFalse)
+	})
+	Convey("Tags and measurements should be escaped", t, func() {
+		p := Point{Measurement: "my metar", Tags: map[string]string{"station": "A,B=C D", "empty": ""}, Fields: map[string]interface{}{"x": 1.5}, Time: time.Unix(0, 5)}
+		So(p.LineProtocol(), ShouldEqual, `my\ metar,station=A\,B\=C\ D x=1.5 5`)
+	})
+	Convey("Writer should batch the lines", t, func() {
+		var buf bytes.Buffer
+		w := NewLineProtocolWriter(&buf, 2)
+		r := newMETARresponse([]METAR{ulli, {StationID: "NIL"}, ulli, ulli})
+		So(w.WriteMETARs(r), ShouldBeNil)
+		So(strings.Count(buf.String(), "\n"), ShouldEqual, 2)
+		So(w.Flush(), ShouldBeNil)
+		So(strings.Count(buf.String(), "\n"), ShouldEqual, 3)
+	})
+}
@@ -0,0 +1,928 @@
+package addstogo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IWXXM namespaces used by MarshalIWXXMMetars and MarshalIWXXMTafs.
+const (
+	IWXXMNamespace   = "http://icao.int/iwxxm/3.0"
+	gmlNamespace     = "http://www.opengis.net/gml/3.2"
+	aixmNamespace    = "http://www.aixm.aero/schema/5.1.1"
+	xlinkNamespace   = "http://www.w3.org/1999/xlink"
+	collectNamespace = "http://def.wmo.int/collect/2014"
+)
+
+const (
+	metresPerStatuteMile = 1609.344
+	feetPerMetre         = 3.28084
+	knotsPerMetreSecond  = 1.943844
+	knotsPerKmHour       = 1 / 1.852
+)
+
+// WMO code registers referenced from IWXXM documents.
+const (
+	wmoWeatherCodes     = "http://codes.wmo.int/306/4678/"
+	wmoCloudAmountCodes = "http://codes.wmo.int/49-2/CloudAmountReportedAtAerodrome/"
+	wmoCloudTypeCodes   = "http://codes.wmo.int/49-2/SigConvectiveCloudType/"
+	wmoNilNSC           = "http://codes.wmo.int/common/nil/nothingOfOperationalSignificance"
+	wmoNilNCD           = "http://codes.wmo.int/common/nil/notDetectedByAutoSystem"
+)
+
+// iwxxmMeasure is a numeric value with unit of measure. Missing values carry a nilReason.
+type iwxxmMeasure struct {
+	Uom   string `xml:"uom,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (m iwxxmMeasure) float() (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(m.Value), 64)
+	return v, err == nil
+}
+
+// knots returns the speed in knots.
+func (m iwxxmMeasure) knots() int {
+	v, ok := m.float()
+	if !ok {
+		return 0
+	}
+	switch m.Uom {
+	case "m/s":
+		v *= knotsPerMetreSecond
+	case "km/h":
+		v *= knotsPerKmHour
+	}
+	return int(math.Round(v))
+}
+
+// feet returns the height in feet.
+func (m iwxxmMeasure) feet() int {
+	v, ok := m.float()
+	if !ok {
+		return 0
+	}
+	if m.Uom == "m" {
+		v *= feetPerMetre
+	}
+	return int(math.Round(v))
+}
+
+// statuteMiles returns the distance in statute miles, rounded to 0.01 as the dataserver does.
+func (m iwxxmMeasure) statuteMiles() float32 {
+	v, ok := m.float()
+	if !ok {
+		return 0
+	}
+	switch m.Uom {
+	case "m":
+		v /= metresPerStatuteMile
+	case "km":
+		v = v * 1000 / metresPerStatuteMile
+	}
+	return float32(math.Round(v*100) / 100)
+}
+
+// inHg returns the pressure in inches of mercury.
+func (m iwxxmMeasure) inHg() float32 {
+	v, ok := m.float()
+	if !ok {
+		return 0
+	}
+	if m.Uom == "hPa" {
+		v *= inHgPerHPa
+	}
+	return float32(v)
+}
+
+type iwxxmLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// code returns the last path segment of a WMO code register reference.
+func (l iwxxmLink) code() string {
+	return l.Href[strings.LastIndex(l.Href, "/")+1:]
+}
+
+type iwxxmAerodrome struct {
+	Designator            string       `xml:"designator"`
+	LocationIndicatorICAO string       `xml:"locationIndicatorICAO"`
+	Pos                   string       `xml:"ARP>ElevatedPoint>pos"`
+	Elevation             iwxxmMeasure `xml:"ARP>ElevatedPoint>elevation"`
+}
+
+func (a iwxxmAerodrome) stationID() string {
+	if a.LocationIndicatorICAO != "" {
+		return a.LocationIndicatorICAO
+	}
+	return a.Designator
+}
+
+func (a iwxxmAerodrome) position() (lat, lon, elevation float32) {
+	fields := strings.Fields(a.Pos)
+	if len(fields) == 2 {
+		la, _ := strconv.ParseFloat(fields[0], 32)
+		lo, _ := strconv.ParseFloat(fields[1], 32)
+		lat, lon = float32(la), float32(lo)
+	}
+	if v, ok := a.Elevation.float(); ok {
+		if a.Elevation.Uom == "FT" || a.Elevation.Uom == "[ft_i]" {
+			v /= feetPerMetre
+		}
+		elevation = float32(v)
+	}
+	return
+}
+
+type iwxxmWind struct {
+	Variable  bool         `xml:"variableWindDirection,attr"`
+	Direction iwxxmMeasure `xml:"meanWindDirection"`
+	Speed     iwxxmMeasure `xml:"meanWindSpeed"`
+	Gust      iwxxmMeasure `xml:"windGustSpeed"`
+}
+
+func (w iwxxmWind) values() (dir, speed, gust int) {
+	if v, ok := w.Direction.float(); ok && !w.Variable {
+		dir = int(math.Round(v))
+	}
+	return dir, w.Speed.knots(), w.Gust.knots()
+}
+
+type iwxxmCloudLayer struct {
+	Amount    iwxxmLink    `xml:"amount"`
+	Base      iwxxmMeasure `xml:"base"`
+	CloudType iwxxmLink    `xml:"cloudType"`
+}
+
+type iwxxmCloud struct {
+	NilReason          string            `xml:"nilReason,attr"`
+	VerticalVisibility iwxxmMeasure      `xml:"AerodromeCloud>verticalVisibility"`
+	Layers             []iwxxmCloudLayer `xml:"AerodromeCloud>layer>CloudLayer"`
+	ForecastVV         iwxxmMeasure      `xml:"AerodromeCloudForecast>verticalVisibility"`
+	ForecastLayers     []iwxxmCloudLayer `xml:"AerodromeCloudForecast>layer>CloudLayer"`
+}
+
+// skyCondition converts the cloud group, returning the vertical visibility separately.
+func (c *iwxxmCloud) skyCondition(cavok bool) (sky []SkyCondition, vertVisFt int) {
+	switch {
+	case cavok:
+		return []SkyCondition{{SkyCover: "CAVOK"}}, 0
+	case c == nil:
+		return nil, 0
+	case c.NilReason == wmoNilNSC:
+		return []SkyCondition{{SkyCover: "NSC"}}, 0
+	case c.NilReason == wmoNilNCD:
+		return []SkyCondition{{SkyCover: "NCD"}}, 0
+	}
+	vv, layers := c.VerticalVisibility, c.Layers
+	if len(layers) == 0 && c.ForecastLayers != nil {
+		layers = c.ForecastLayers
+	}
+	if vv.Value == "" {
+		vv = c.ForecastVV
+	}
+	if _, ok := vv.float(); ok {
+		vertVisFt = vv.feet()
+		sky = append(sky, SkyCondition{SkyCover: "OVX", CloudBaseFtAgl: 0})
+	}
+	for _, l := range layers {
+		sky = append(sky, SkyCondition{SkyCover: l.Amount.code(), CloudBaseFtAgl: l.Base.feet(), CloudType: l.CloudType.code()})
+	}
+	return
+}
+
+func weatherString(links []iwxxmLink) string {
+	codes := make([]string, 0, len(links))
+	for _, l := range links {
+		if code := l.code(); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, " ")
+}
+
+type iwxxmObservation struct {
+	CAVOK               bool         `xml:"cloudAndVisibilityOK,attr"`
+	AirTemperature      iwxxmMeasure `xml:"airTemperature"`
+	DewpointTemperature iwxxmMeasure `xml:"dewpointTemperature"`
+	QNH                 iwxxmMeasure `xml:"qnh"`
+	SurfaceWind         iwxxmWind    `xml:"surfaceWind>AerodromeSurfaceWind"`
+	Visibility          iwxxmMeasure `xml:"visibility>AerodromeHorizontalVisibility>prevailingVisibility"`
+	PresentWeather      []iwxxmLink  `xml:"presentWeather"`
+	Cloud               *iwxxmCloud  `xml:"cloud"`
+}
+
+type iwxxmMetar struct {
+	XMLName         xml.Name
+	ReportStatus    string           `xml:"reportStatus,attr"`
+	Automated       bool             `xml:"automatedStation,attr"`
+	IssueTime       string           `xml:"issueTime>TimeInstant>timePosition"`
+	ObservationTime string           `xml:"observationTime>TimeInstant>timePosition"`
+	Aerodrome       iwxxmAerodrome   `xml:"aerodrome>AirportHeliport>timeSlice>AirportHeliportTimeSlice"`
+	Observation     iwxxmObservation `xml:"observation>MeteorologicalAerodromeObservation"`
+}
+
+func parseIWXXMTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// tacSpeedUnits maps IWXXM speed units onto the ones of the traditional alphanumeric code.
+var tacSpeedUnits = map[string]string{
+	"m/s":  "MPS",
+	"km/h": "KMH",
+}
+
+// tacTemperature formats a temperature as in the TAC temperature group.
+func tacTemperature(v float64) string {
+	if v = math.Round(v); v < 0 {
+		return fmt.Sprintf("M%02.0f", -v)
+	}
+	return fmt.Sprintf("%02.0f", v)
+}
+
+// rawText rebuilds the report in the traditional alphanumeric code from the groups present in the document,
+// so that reported zeros, like a calm wind or 0 °C, are told apart from missing values.
+func (m iwxxmMetar) rawText(station string, observed time.Time) string {
+	o := m.Observation
+	groups := []string{station, observed.Format("021504Z")}
+	if m.Automated {
+		groups = append(groups, "AUTO")
+	}
+	if speed, ok := o.SurfaceWind.Speed.float(); ok {
+		unit, known := tacSpeedUnits[o.SurfaceWind.Speed.Uom]
+		if !known {
+			unit = "KT"
+		}
+		wind := "VRB"
+		if dir, ok := o.SurfaceWind.Direction.float(); ok && !o.SurfaceWind.Variable {
+			wind = fmt.Sprintf("%03.0f", dir)
+		}
+		wind += fmt.Sprintf("%02.0f", speed)
+		if gust, ok := o.SurfaceWind.Gust.float(); ok {
+			wind += fmt.Sprintf("G%02.0f", gust)
+		}
+		groups = append(groups, wind+unit)
+	}
+	if o.CAVOK {
+		groups = append(groups, "CAVOK")
+	} else if v, ok := o.Visibility.float(); ok {
+		if o.Visibility.Uom == "km" {
+			v *= 1000
+		}
+		groups = append(groups, fmt.Sprintf("%04.0f", math.Min(v, 9999)))
+	}
+	if wx := weatherString(o.PresentWeather); wx != "" {
+		groups = append(groups, wx)
+	}
+	if c := o.Cloud; c != nil && !o.CAVOK {
+		sky, vertVisFt := c.skyCondition(false)
+		for _, s := range sky {
+			switch s.SkyCover {
+			case "OVX":
+				groups = append(groups, fmt.Sprintf("VV%03d", vertVisFt/100))
+			case "NSC", "NCD":
+				groups = append(groups, s.SkyCover)
+			default:
+				groups = append(groups, fmt.Sprintf("%s%03d%s", s.SkyCover, s.CloudBaseFtAgl/100, s.CloudType))
+			}
+		}
+	}
+	if t, ok := o.AirTemperature.float(); ok {
+		group := tacTemperature(t) + "/"
+		if d, ok := o.DewpointTemperature.float(); ok {
+			group += tacTemperature(d)
+		}
+		groups = append(groups, group)
+	}
+	if q, ok := o.QNH.float(); ok {
+		if o.QNH.Uom == "hPa" {
+			groups = append(groups, fmt.Sprintf("Q%04.0f", q))
+		} else {
+			groups = append(groups, fmt.Sprintf("A%04.0f", q*100))
+		}
+	}
+	return strings.Join(groups, " ")
+}
+
+func (m iwxxmMetar) metar() METAR {
+	o := m.Observation
+	result := METAR{
+		StationID:       m.Aerodrome.stationID(),
+		ObservationTime: parseIWXXMTime(m.ObservationTime),
+		AltimInHg:       o.QNH.inHg(),
+		WxString:        weatherString(o.PresentWeather),
+		MetarType:       m.XMLName.Local,
+	}
+	if result.ObservationTime.IsZero() {
+		result.ObservationTime = parseIWXXMTime(m.IssueTime)
+	}
+	result.Latitude, result.Longitude, result.ElevationM = m.Aerodrome.position()
+	if v, ok := o.AirTemperature.float(); ok {
+		result.TempC = float32(v)
+	}
+	if v, ok := o.DewpointTemperature.float(); ok {
+		result.DewpointC = float32(v)
+	}
+	result.WindDirDegrees, result.WindSpeedKt, result.WindGustKt = o.SurfaceWind.values()
+	if o.CAVOK {
+		result.VisibilityStatuteMi = 6.21
+	} else {
+		result.VisibilityStatuteMi = o.Visibility.statuteMiles()
+	}
+	result.SkyCondition, result.VertVisFt = o.Cloud.skyCondition(o.CAVOK)
+	result.QualityControlFlags.Corrected = m.ReportStatus == "CORRECTION"
+	result.QualityControlFlags.Auto = m.Automated
+	result.RawText = m.rawText(result.StationID, result.ObservationTime)
+	return result
+}
+
+type iwxxmTemperature struct {
+	Max     iwxxmMeasure `xml:"maximumAirTemperature"`
+	MaxTime string       `xml:"maximumAirTemperatureTime>TimeInstant>timePosition"`
+	Min     iwxxmMeasure `xml:"minimumAirTemperature"`
+	MinTime string       `xml:"minimumAirTemperatureTime>TimeInstant>timePosition"`
+}
+
+type iwxxmForecast struct {
+	ChangeIndicator string             `xml:"changeIndicator,attr"`
+	CAVOK           bool               `xml:"cloudAndVisibilityOK,attr"`
+	Begin           string             `xml:"phenomenonTime>TimePeriod>beginPosition"`
+	End             string             `xml:"phenomenonTime>TimePeriod>endPosition"`
+	Visibility      iwxxmMeasure       `xml:"prevailingVisibility"`
+	SurfaceWind     iwxxmWind          `xml:"surfaceWind>AerodromeSurfaceWindForecast"`
+	Weather         []iwxxmLink        `xml:"weather"`
+	Cloud           *iwxxmCloud        `xml:"cloud"`
+	Temperature     []iwxxmTemperature `xml:"temperature>AerodromeAirTemperatureForecast"`
+}
+
+// changeIndicators maps IWXXM change indicators onto the dataserver ones.
+var changeIndicators = map[string]string{
+	"BECOMING":               "BECMG",
+	"TEMPORARY_FLUCTUATIONS": "TEMPO",
+	"FROM":                   "FM",
+}
+
+func (f iwxxmForecast) forecast() Forecast {
+	result := Forecast{
+		FcstTimeFrom: parseIWXXMTime(f.Begin),
+		FcstTimeTo:   parseIWXXMTime(f.End),
+		WxString:     weatherString(f.Weather),
+	}
+	indicator := f.ChangeIndicator
+	if strings.HasPrefix(indicator, "PROBABILITY_") {
+		parts := strings.SplitN(strings.TrimPrefix(indicator, "PROBABILITY_"), "_", 2)
+		result.Probability = parts[0]
+		indicator = "PROB"
+		if len(parts) == 2 {
+			indicator = parts[1]
+		}
+	}
+	if ci, ok := changeIndicators[indicator]; ok {
+		indicator = ci
+	}
+	result.ChangeIndicator = indicator
+	if indicator == "BECMG" {
+		result.TimeBecoming = result.FcstTimeTo
+	}
+	result.WindDirDegrees, result.WindSpeedKt, result.WindGustKt = f.SurfaceWind.values()
+	if f.CAVOK {
+		result.VisibilityStatuteMi = 6.21
+	} else {
+		result.VisibilityStatuteMi = f.Visibility.statuteMiles()
+	}
+	result.SkyCondition, result.VertVisFt = f.Cloud.skyCondition(f.CAVOK)
+	for _, t := range f.Temperature {
+		if t.Max.Value != "" {
+			result.Temperature = append(result.Temperature, Temperature{ValidTime: parseIWXXMTime(t.MaxTime), MaxTempC: strings.TrimSpace(t.Max.Value)})
+		}
+		if t.Min.Value != "" {
+			result.Temperature = append(result.Temperature, Temperature{ValidTime: parseIWXXMTime(t.MinTime), MinTempC: strings.TrimSpace(t.Min.Value)})
+		}
+	}
+	return result
+}
+
+type iwxxmTaf struct {
+	ReportStatus   string          `xml:"reportStatus,attr"`
+	IssueTime      string          `xml:"issueTime>TimeInstant>timePosition"`
+	Aerodrome      iwxxmAerodrome  `xml:"aerodrome>AirportHeliport>timeSlice>AirportHeliportTimeSlice"`
+	ValidFrom      string          `xml:"validPeriod>TimePeriod>beginPosition"`
+	ValidTo        string          `xml:"validPeriod>TimePeriod>endPosition"`
+	BaseForecast   *iwxxmForecast  `xml:"baseForecast>MeteorologicalAerodromeForecast"`
+	ChangeForecast []iwxxmForecast `xml:"changeForecast>MeteorologicalAerodromeForecast"`
+}
+
+func (t iwxxmTaf) taf() TAF {
+	result := TAF{
+		StationID:     t.Aerodrome.stationID(),
+		IssueTime:     parseIWXXMTime(t.IssueTime),
+		ValidTimeFrom: parseIWXXMTime(t.ValidFrom),
+		ValidTimeTo:   parseIWXXMTime(t.ValidTo),
+	}
+	result.Latitude, result.Longitude, result.ElevationM = t.Aerodrome.position()
+	if t.BaseForecast != nil {
+		base := t.BaseForecast.forecast()
+		base.FcstTimeFrom = result.ValidTimeFrom
+		result.Forecast = append(result.Forecast, base)
+	}
+	for _, f := range t.ChangeForecast {
+		result.Forecast = append(result.Forecast, f.forecast())
+	}
+	// as in the dataserver output the base, BECMG and FM periods last until the next of them
+	for i := range result.Forecast {
+		f := &result.Forecast[i]
+		if f.ChangeIndicator != "" && f.ChangeIndicator != "BECMG" && f.ChangeIndicator != "FM" {
+			continue
+		}
+		f.FcstTimeTo = result.ValidTimeTo
+		for _, next := range result.Forecast[i+1:] {
+			if next.ChangeIndicator == "BECMG" || next.ChangeIndicator == "FM" {
+				f.FcstTimeTo = next.FcstTimeFrom
+				break
+			}
+		}
+	}
+	return result
+}
+
+// decodeIWXXM calls fn for every element with one of the given local names,
+// so that single reports and collect:MeteorologicalBulletin are read alike.
+func decodeIWXXM(input []byte, fn func(d *xml.Decoder, start xml.StartElement) error, names ...string) error {
+	d := xml.NewDecoder(bytes.NewReader(input))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && strings.HasPrefix(start.Name.Space, "http://icao.int/iwxxm/") {
+			for _, name := range names {
+				if start.Name.Local == name {
+					if err = fn(d, start); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+// UnmarshalIWXXMMetars decodes IWXXM METAR and SPECI reports, either standalone or in a bulletin.
+func UnmarshalIWXXMMetars(input []byte) (result *METARresponse, err error) {
+	var metars []METAR
+	err = decodeIWXXM(input, func(d *xml.Decoder, start xml.StartElement) error {
+		var m iwxxmMetar
+		if err := d.DecodeElement(&m, &start); err != nil {
+			return err
+		}
+		metars = append(metars, m.metar())
+		return nil
+	}, "METAR", "SPECI")
+	if err != nil {
+		return nil, err
+	}
+	return newMETARresponse(metars), nil
+}
+
+// UnmarshalIWXXMTafs decodes IWXXM TAF reports, either standalone or in a bulletin.
+func UnmarshalIWXXMTafs(input []byte) (result *TAFresponse, err error) {
+	var tafs []TAF
+	err = decodeIWXXM(input, func(d *xml.Decoder, start xml.StartElement) error {
+		var t iwxxmTaf
+		if err := d.DecodeElement(&t, &start); err != nil {
+			return err
+		}
+		tafs = append(tafs, t.taf())
+		return nil
+	}, "TAF")
+	if err != nil {
+		return nil, err
+	}
+	return newTAFresponse(tafs), nil
+}
+
+// Encoding structures. The element names carry their prefixes literally,
+// the namespaces are declared once on the bulletin.
+
+type iwxxmOutMeasure struct {
+	Uom   string `xml:"uom,attr"`
+	Value string `xml:",chardata"`
+}
+
+func measure(uom string, v float64, prec int) *iwxxmOutMeasure {
+	return &iwxxmOutMeasure{Uom: uom, Value: strconv.FormatFloat(v, 'f', prec, 64)}
+}
+
+type iwxxmOutLink struct {
+	Href string `xml:"xlink:href,attr"`
+}
+
+type iwxxmOutTimeInstant struct {
+	ID           string `xml:"gml:id,attr"`
+	TimePosition string `xml:"gml:timePosition"`
+}
+
+type iwxxmOutTimePeriod struct {
+	ID            string `xml:"gml:id,attr"`
+	BeginPosition string `xml:"gml:beginPosition"`
+	EndPosition   string `xml:"gml:endPosition"`
+}
+
+type iwxxmOutAerodrome struct {
+	ID        string `xml:"gml:id,attr"`
+	TimeSlice struct {
+		ID                    string `xml:"gml:id,attr"`
+		ValidTime             string `xml:"gml:validTime"`
+		Interpretation        string `xml:"aixm:interpretation"`
+		Designator            string `xml:"aixm:designator"`
+		LocationIndicatorICAO string `xml:"aixm:locationIndicatorICAO"`
+		ARP                   struct {
+			ID           string           `xml:"gml:id,attr"`
+			SrsName      string           `xml:"srsName,attr"`
+			AxisLabels   string           `xml:"axisLabels,attr"`
+			SrsDimension string           `xml:"srsDimension,attr"`
+			Pos          string           `xml:"gml:pos"`
+			Elevation    *iwxxmOutMeasure `xml:"aixm:elevation"`
+		} `xml:"aixm:ARP>aixm:ElevatedPoint"`
+	} `xml:"aixm:timeSlice>aixm:AirportHeliportTimeSlice"`
+}
+
+type iwxxmOutWind struct {
+	Variable  bool             `xml:"variableWindDirection,attr"`
+	Direction *iwxxmOutMeasure `xml:"iwxxm:meanWindDirection"`
+	Speed     *iwxxmOutMeasure `xml:"iwxxm:meanWindSpeed"`
+	Gust      *iwxxmOutMeasure `xml:"iwxxm:windGustSpeed"`
+}
+
+type iwxxmOutCloudLayer struct {
+	Amount    iwxxmOutLink     `xml:"iwxxm:amount"`
+	Base      *iwxxmOutMeasure `xml:"iwxxm:base"`
+	CloudType *iwxxmOutLink    `xml:"iwxxm:cloudType"`
+}
+
+type iwxxmOutCloud struct {
+	NilReason          string
+	VerticalVisibility *iwxxmOutMeasure
+	Layers             []iwxxmOutCloudLayer
+}
+
+// marshal writes the cloud group wrapped in the element expected by the report type,
+// AerodromeCloud for observations and AerodromeCloudForecast for forecasts.
+func (c iwxxmOutCloud) marshal(e *xml.Encoder, start xml.StartElement, wrapper string) error {
+	if c.NilReason != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "nilReason"}, Value: c.NilReason})
+		return e.EncodeElement("", start)
+	}
+	inner := struct {
+		XMLName            xml.Name
+		VerticalVisibility *iwxxmOutMeasure     `xml:"iwxxm:verticalVisibility"`
+		Layers             []iwxxmOutCloudLayer `xml:"iwxxm:layer>iwxxm:CloudLayer"`
+	}{XMLName: xml.Name{Local: wrapper}, VerticalVisibility: c.VerticalVisibility, Layers: c.Layers}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.Encode(inner); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+type iwxxmOutObservationCloud struct{ iwxxmOutCloud }
+
+func (c iwxxmOutObservationCloud) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return c.marshal(e, start, "iwxxm:AerodromeCloud")
+}
+
+type iwxxmOutForecastCloud struct{ iwxxmOutCloud }
+
+func (c iwxxmOutForecastCloud) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return c.marshal(e, start, "iwxxm:AerodromeCloudForecast")
+}
+
+type iwxxmOutObservation struct {
+	ID                  string                    `xml:"gml:id,attr"`
+	CAVOK               bool                      `xml:"cloudAndVisibilityOK,attr"`
+	AirTemperature      *iwxxmOutMeasure          `xml:"iwxxm:airTemperature"`
+	DewpointTemperature *iwxxmOutMeasure          `xml:"iwxxm:dewpointTemperature"`
+	QNH                 *iwxxmOutMeasure          `xml:"iwxxm:qnh"`
+	SurfaceWind         *iwxxmOutWind             `xml:"iwxxm:surfaceWind>iwxxm:AerodromeSurfaceWind"`
+	Visibility          *iwxxmOutMeasure          `xml:"iwxxm:visibility>iwxxm:AerodromeHorizontalVisibility>iwxxm:prevailingVisibility"`
+	PresentWeather      []iwxxmOutLink            `xml:"iwxxm:presentWeather"`
+	Cloud               *iwxxmOutObservationCloud `xml:"iwxxm:cloud"`
+}
+
+type iwxxmOutMetar struct {
+	XMLName         xml.Name
+	ID              string              `xml:"gml:id,attr"`
+	ReportStatus    string              `xml:"reportStatus,attr"`
+	Automated       bool                `xml:"automatedStation,attr"`
+	IssueTime       iwxxmOutTimeInstant `xml:"iwxxm:issueTime>gml:TimeInstant"`
+	Aerodrome       iwxxmOutAerodrome   `xml:"iwxxm:aerodrome>aixm:AirportHeliport"`
+	ObservationTime iwxxmOutLink        `xml:"iwxxm:observationTime"`
+	Observation     iwxxmOutObservation `xml:"iwxxm:observation>iwxxm:MeteorologicalAerodromeObservation"`
+}
+
+type iwxxmOutTemperature struct {
+	Max     *iwxxmOutMeasure     `xml:"iwxxm:maximumAirTemperature"`
+	MaxTime *iwxxmOutTimeInstant `xml:"iwxxm:maximumAirTemperatureTime>gml:TimeInstant"`
+	Min     *iwxxmOutMeasure     `xml:"iwxxm:minimumAirTemperature"`
+	MinTime *iwxxmOutTimeInstant `xml:"iwxxm:minimumAirTemperatureTime>gml:TimeInstant"`
+}
+
+type iwxxmOutForecast struct {
+	ID              string                 `xml:"gml:id,attr"`
+	ChangeIndicator string                 `xml:"changeIndicator,attr,omitempty"`
+	CAVOK           bool                   `xml:"cloudAndVisibilityOK,attr"`
+	PhenomenonTime  iwxxmOutTimePeriod     `xml:"iwxxm:phenomenonTime>gml:TimePeriod"`
+	Visibility      *iwxxmOutMeasure       `xml:"iwxxm:prevailingVisibility"`
+	SurfaceWind     *iwxxmOutWind          `xml:"iwxxm:surfaceWind>iwxxm:AerodromeSurfaceWindForecast"`
+	Weather         []iwxxmOutLink         `xml:"iwxxm:weather"`
+	Cloud           *iwxxmOutForecastCloud `xml:"iwxxm:cloud"`
+	Temperature     []iwxxmOutTemperature  `xml:"iwxxm:temperature>iwxxm:AerodromeAirTemperatureForecast"`
+}
+
+type iwxxmOutTaf struct {
+	XMLName        xml.Name            `xml:"iwxxm:TAF"`
+	ID             string              `xml:"gml:id,attr"`
+	ReportStatus   string              `xml:"reportStatus,attr"`
+	IssueTime      iwxxmOutTimeInstant `xml:"iwxxm:issueTime>gml:TimeInstant"`
+	Aerodrome      iwxxmOutAerodrome   `xml:"iwxxm:aerodrome>aixm:AirportHeliport"`
+	ValidPeriod    iwxxmOutTimePeriod  `xml:"iwxxm:validPeriod>gml:TimePeriod"`
+	BaseForecast   *iwxxmOutForecast   `xml:"iwxxm:baseForecast>iwxxm:MeteorologicalAerodromeForecast"`
+	ChangeForecast []iwxxmOutForecast  `xml:"iwxxm:changeForecast>iwxxm:MeteorologicalAerodromeForecast"`
+}
+
+type iwxxmOutBulletin struct {
+	XMLName            xml.Name              `xml:"collect:MeteorologicalBulletin"`
+	XmlnsCollect       string                `xml:"xmlns:collect,attr"`
+	XmlnsIWXXM         string                `xml:"xmlns:iwxxm,attr"`
+	XmlnsGML           string                `xml:"xmlns:gml,attr"`
+	XmlnsAIXM          string                `xml:"xmlns:aixm,attr"`
+	XmlnsXlink         string                `xml:"xmlns:xlink,attr"`
+	ID                 string                `xml:"gml:id,attr"`
+	Information        []iwxxmOutInformation `xml:"collect:meteorologicalInformation"`
+	BulletinIdentifier string                `xml:"collect:bulletinIdentifier"`
+}
+
+// iwxxmOutInformation wraps a report, which is named by its own XMLName.
+type iwxxmOutInformation struct {
+	Report interface{}
+}
+
+func newIWXXMBulletin(id string) *iwxxmOutBulletin {
+	return &iwxxmOutBulletin{
+		XmlnsCollect: collectNamespace,
+		XmlnsIWXXM:   IWXXMNamespace,
+		XmlnsGML:     gmlNamespace,
+		XmlnsAIXM:    aixmNamespace,
+		XmlnsXlink:   xlinkNamespace,
+		ID:           "bulletin-" + id,
+	}
+}
+
+func (b *iwxxmOutBulletin) marshal() ([]byte, error) {
+	output, err := xml.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+// iwxxmID builds a gml:id, which must be an NCName unique within the bulletin.
+func iwxxmID(kind, station string, t time.Time, n int) string {
+	return fmt.Sprintf("%s-%s-%s-%d", kind, station, t.UTC().Format("20060102T1504Z"), n)
+}
+
+func iwxxmTimeString(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func newIWXXMAerodrome(station string, lat, lon, elevation float32, t time.Time, n int) iwxxmOutAerodrome {
+	var a iwxxmOutAerodrome
+	a.ID = iwxxmID("aerodrome", station, t, n)
+	a.TimeSlice.ID = iwxxmID("aerodrome-ts", station, t, n)
+	a.TimeSlice.Interpretation = "SNAPSHOT"
+	a.TimeSlice.Designator = station
+	a.TimeSlice.LocationIndicatorICAO = station
+	a.TimeSlice.ARP.ID = iwxxmID("aerodrome-arp", station, t, n)
+	a.TimeSlice.ARP.SrsName = "http://www.opengis.net/def/crs/EPSG/0/4326"
+	a.TimeSlice.ARP.AxisLabels = "Lat Long"
+	a.TimeSlice.ARP.SrsDimension = "2"
+	a.TimeSlice.ARP.Pos = strconv.FormatFloat(float64(lat), 'f', -1, 32) + " " + strconv.FormatFloat(float64(lon), 'f', -1, 32)
+	a.TimeSlice.ARP.Elevation = measure("M", float64(elevation), 0)
+	return a
+}
+
+func newIWXXMWind(dir, speed, gust int) *iwxxmOutWind {
+	w := &iwxxmOutWind{Speed: measure("[kn_i]", float64(speed), 0)}
+	if dir == 0 && speed > 0 {
+		w.Variable = true
+	} else {
+		w.Direction = measure("deg", float64(dir), 0)
+	}
+	if gust > 0 {
+		w.Gust = measure("[kn_i]", float64(gust), 0)
+	}
+	return w
+}
+
+func newIWXXMWeather(wx string) (links []iwxxmOutLink) {
+	for _, code := range strings.Fields(wx) {
+		links = append(links, iwxxmOutLink{Href: wmoWeatherCodes + code})
+	}
+	return
+}
+
+// newIWXXMCloud converts the sky condition; it reports whether CAVOK was found.
+func newIWXXMCloud(sky []SkyCondition, vertVisFt int) (cloud iwxxmOutCloud, cavok bool) {
+	for _, s := range sky {
+		switch s.SkyCover {
+		case "CAVOK":
+			return cloud, true
+		case "NSC", "SKC", "CLR":
+			cloud.NilReason = wmoNilNSC
+			return cloud, false
+		case "NCD":
+			cloud.NilReason = wmoNilNCD
+			return cloud, false
+		case "OVX":
+			cloud.VerticalVisibility = measure("[ft_i]", float64(vertVisFt), 0)
+		default:
+			layer := iwxxmOutCloudLayer{
+				Amount: iwxxmOutLink{Href: wmoCloudAmountCodes + s.SkyCover},
+				Base:   measure("[ft_i]", float64(s.CloudBaseFtAgl), 0),
+			}
+			if s.CloudType != "" {
+				layer.CloudType = &iwxxmOutLink{Href: wmoCloudTypeCodes + s.CloudType}
+			}
+			cloud.Layers = append(cloud.Layers, layer)
+		}
+	}
+	return cloud, false
+}
+
+// visibilityMetres converts the visibility back to metres; 6.21 sm is the dataserver's 10 km or more.
+func visibilityMetres(sm float32) *iwxxmOutMeasure {
+	if sm >= 6.21 {
+		return measure("m", 10000, 0)
+	}
+	return measure("m", math.Round(float64(sm)*metresPerStatuteMile), 0)
+}
+
+func newIWXXMMetar(m METAR, n int) iwxxmOutMetar {
+	kind := "METAR"
+	if m.MetarType == "SPECI" {
+		kind = "SPECI"
+	}
+	out := iwxxmOutMetar{
+		XMLName:      xml.Name{Local: "iwxxm:" + kind},
+		ID:           iwxxmID(strings.ToLower(kind), m.StationID, m.ObservationTime, n),
+		ReportStatus: "NORMAL",
+		Automated:    m.QualityControlFlags.Auto,
+		Aerodrome:    newIWXXMAerodrome(m.StationID, m.Latitude, m.Longitude, m.ElevationM, m.ObservationTime, n),
+	}
+	if m.QualityControlFlags.Corrected {
+		out.ReportStatus = "CORRECTION"
+	}
+	out.IssueTime = iwxxmOutTimeInstant{ID: iwxxmID("ti", m.StationID, m.ObservationTime, n), TimePosition: iwxxmTimeString(m.ObservationTime)}
+	out.ObservationTime = iwxxmOutLink{Href: "#" + out.IssueTime.ID}
+	cloud, cavok := newIWXXMCloud(m.SkyCondition, m.VertVisFt)
+	out.Observation = iwxxmOutObservation{
+		ID:                  iwxxmID("obs", m.StationID, m.ObservationTime, n),
+		CAVOK:               cavok,
+		AirTemperature:      measure("Cel", float64(m.TempC), 0),
+		DewpointTemperature: measure("Cel", float64(m.DewpointC), 0),
+		QNH:                 measure("hPa", float64(m.AltimInHg)/inHgPerHPa, 0),
+		SurfaceWind:         newIWXXMWind(m.WindDirDegrees, m.WindSpeedKt, m.WindGustKt),
+		PresentWeather:      newIWXXMWeather(m.WxString),
+	}
+	if !cavok {
+		out.Observation.Visibility = visibilityMetres(m.VisibilityStatuteMi)
+		if len(m.SkyCondition) > 0 {
+			out.Observation.Cloud = &iwxxmOutObservationCloud{cloud}
+		}
+	}
+	return out
+}
+
+// iwxxmChangeIndicator is the reverse of changeIndicators, taking PROBnn into account.
+func iwxxmChangeIndicator(f Forecast) string {
+	var indicator string
+	for k, v := range changeIndicators {
+		if v == f.ChangeIndicator {
+			indicator = k
+		}
+	}
+	if f.Probability != "" {
+		if indicator == "" {
+			return "PROBABILITY_" + f.Probability
+		}
+		return "PROBABILITY_" + f.Probability + "_" + indicator
+	}
+	return indicator
+}
+
+func newIWXXMForecast(f Forecast, station string, n int) iwxxmOutForecast {
+	out := iwxxmOutForecast{
+		ID:              iwxxmID("fcst", station, f.FcstTimeFrom, n),
+		ChangeIndicator: iwxxmChangeIndicator(f),
+		Weather:         newIWXXMWeather(f.WxString),
+	}
+	end := f.FcstTimeTo
+	if f.ChangeIndicator == "BECMG" && !f.TimeBecoming.IsZero() {
+		end = f.TimeBecoming
+	}
+	out.PhenomenonTime = iwxxmOutTimePeriod{ID: iwxxmID("tp", station, f.FcstTimeFrom, n), BeginPosition: iwxxmTimeString(f.FcstTimeFrom), EndPosition: iwxxmTimeString(end)}
+	if f.WindSpeedKt > 0 || f.WindDirDegrees > 0 {
+		out.SurfaceWind = newIWXXMWind(f.WindDirDegrees, f.WindSpeedKt, f.WindGustKt)
+	}
+	cloud, cavok := newIWXXMCloud(f.SkyCondition, f.VertVisFt)
+	out.CAVOK = cavok
+	if !cavok {
+		if f.VisibilityStatuteMi > 0 {
+			out.Visibility = visibilityMetres(f.VisibilityStatuteMi)
+		}
+		if len(f.SkyCondition) > 0 {
+			out.Cloud = &iwxxmOutForecastCloud{cloud}
+		}
+	}
+	for i, t := range f.Temperature {
+		var temp iwxxmOutTemperature
+		instant := &iwxxmOutTimeInstant{ID: iwxxmID(fmt.Sprintf("tt%d", i), station, t.ValidTime, n), TimePosition: iwxxmTimeString(t.ValidTime)}
+		if v, err := strconv.ParseFloat(t.MaxTempC, 64); err == nil {
+			temp.Max, temp.MaxTime = measure("Cel", v, 0), instant
+		} else if v, err := strconv.ParseFloat(t.MinTempC, 64); err == nil {
+			temp.Min, temp.MinTime = measure("Cel", v, 0), instant
+		} else {
+			continue
+		}
+		out.Temperature = append(out.Temperature, temp)
+	}
+	return out
+}
+
+func newIWXXMTaf(t TAF, n int) iwxxmOutTaf {
+	out := iwxxmOutTaf{
+		ID:           iwxxmID("taf", t.StationID, t.IssueTime, n),
+		ReportStatus: "NORMAL",
+		IssueTime:    iwxxmOutTimeInstant{ID: iwxxmID("ti", t.StationID, t.IssueTime, n), TimePosition: iwxxmTimeString(t.IssueTime)},
+		Aerodrome:    newIWXXMAerodrome(t.StationID, t.Latitude, t.Longitude, t.ElevationM, t.IssueTime, n),
+		ValidPeriod:  iwxxmOutTimePeriod{ID: iwxxmID("vp", t.StationID, t.IssueTime, n), BeginPosition: iwxxmTimeString(t.ValidTimeFrom), EndPosition: iwxxmTimeString(t.ValidTimeTo)},
+	}
+	forecasts := make([]Forecast, len(t.Forecast))
+	copy(forecasts, t.Forecast)
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		return forecasts[i].ChangeIndicator == "" && forecasts[j].ChangeIndicator != ""
+	})
+	for i, f := range forecasts {
+		if i == 0 && f.ChangeIndicator == "" {
+			f.FcstTimeTo = t.ValidTimeTo
+			base := newIWXXMForecast(f, t.StationID, n*100+i)
+			out.BaseForecast = &base
+			continue
+		}
+		out.ChangeForecast = append(out.ChangeForecast, newIWXXMForecast(f, t.StationID, n*100+i))
+	}
+	return out
+}
+
+// MarshalIWXXMMetars encodes the reports as an IWXXM 3.0 bulletin of METAR and SPECI.
+func MarshalIWXXMMetars(input *METARresponse) ([]byte, error) {
+	if input == nil || len(input.Data.METAR) == 0 {
+		return nil, fmt.Errorf("addstogo: no METAR to encode")
+	}
+	first := input.Data.METAR[0]
+	b := newIWXXMBulletin(iwxxmID("metar", first.StationID, first.ObservationTime, 0))
+	b.BulletinIdentifier = "A_SAXX00" + first.StationID + first.ObservationTime.UTC().Format("021504") + ".xml"
+	for i, m := range input.Data.METAR {
+		b.Information = append(b.Information, iwxxmOutInformation{newIWXXMMetar(m, i)})
+	}
+	return b.marshal()
+}
+
+// MarshalIWXXMTafs encodes the forecasts as an IWXXM 3.0 bulletin of TAF.
+func MarshalIWXXMTafs(input *TAFresponse) ([]byte, error) {
+	if input == nil || len(input.Data.TAF) == 0 {
+		return nil, fmt.Errorf("addstogo: no TAF to encode")
+	}
+	first := input.Data.TAF[0]
+	b := newIWXXMBulletin(iwxxmID("taf", first.StationID, first.IssueTime, 0))
+	b.BulletinIdentifier = "A_FTXX00" + first.StationID + first.IssueTime.UTC().Format("021504") + ".xml"
+	for i, t := range input.Data.TAF {
+		b.Information = append(b.Information, iwxxmOutInformation{newIWXXMTaf(t, i)})
+	}
+	return b.marshal()
+}
@@ -0,0 +1,153 @@
+package addstogo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const iwxxmMetarFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<iwxxm:METAR xmlns:iwxxm="http://icao.int/iwxxm/3.0" xmlns:gml="http://www.opengis.net/gml/3.2" xmlns:aixm="http://www.aixm.aero/schema/5.1.1" xmlns:xlink="http://www.w3.org/1999/xlink" gml:id="uuid.1" reportStatus="NORMAL" automatedStation="false">
+  <iwxxm:issueTime><gml:TimeInstant gml:id="uuid.2"><gml:timePosition>2019-06-10T08:00:00Z</gml:timePosition></gml:TimeInstant></iwxxm:issueTime>
+  <iwxxm:aerodrome><aixm:AirportHeliport gml:id="uuid.3"><aixm:timeSlice><aixm:AirportHeliportTimeSlice gml:id="uuid.4"><gml:validTime/><aixm:interpretation>SNAPSHOT</aixm:interpretation><aixm:designator>ULLI</aixm:designator><aixm:name>PULKOVO</aixm:name><aixm:locationIndicatorICAO>ULLI</aixm:locationIndicatorICAO><aixm:ARP><aixm:ElevatedPoint gml:id="uuid.5" srsName="http://www.opengis.net/def/crs/EPSG/0/4326" axisLabels="Lat Long" srsDimension="2"><gml:pos>59.8 30.27</gml:pos><aixm:elevation uom="M">4</aixm:elevation></aixm:ElevatedPoint></aixm:ARP></aixm:AirportHeliportTimeSlice></aixm:timeSlice></aixm:AirportHeliport></iwxxm:aerodrome>
+  <iwxxm:observationTime xlink:href="#uuid.2"/>
+  <iwxxm:observation>
+    <iwxxm:MeteorologicalAerodromeObservation gml:id="uuid.6" cloudAndVisibilityOK="false">
+      <iwxxm:airTemperature uom="Cel">20</iwxxm:airTemperature>
+      <iwxxm:dewpointTemperature uom="Cel">11</iwxxm:dewpointTemperature>
+      <iwxxm:qnh uom="hPa">1022</iwxxm:qnh>
+      <iwxxm:surfaceWind><iwxxm:AerodromeSurfaceWind variableWindDirection="false"><iwxxm:meanWindDirection uom="deg">230</iwxxm:meanWindDirection><iwxxm:meanWindSpeed uom="m/s">7</iwxxm:meanWindSpeed><iwxxm:extremeClockwiseWindDirection uom="deg">270</iwxxm:extremeClockwiseWindDirection><iwxxm:extremeCounterClockwiseWindDirection uom="deg">210</iwxxm:extremeCounterClockwiseWindDirection></iwxxm:AerodromeSurfaceWind></iwxxm:surfaceWind>
+      <iwxxm:visibility><iwxxm:AerodromeHorizontalVisibility><iwxxm:prevailingVisibility uom="m">10000</iwxxm:prevailingVisibility></iwxxm:AerodromeHorizontalVisibility></iwxxm:visibility>
+      <iwxxm:cloud><iwxxm:AerodromeCloud><iwxxm:layer><iwxxm:CloudLayer><iwxxm:amount xlink:href="http://codes.wmo.int/49-2/CloudAmountReportedAtAerodrome/FEW"/><iwxxm:base uom="[ft_i]">4000</iwxxm:base></iwxxm:CloudLayer></iwxxm:layer></iwxxm:AerodromeCloud></iwxxm:cloud>
+    </iwxxm:MeteorologicalAerodromeObservation>
+  </iwxxm:observation>
+</iwxxm:METAR>`
+
+const iwxxmTafFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<collect:MeteorologicalBulletin xmlns:collect="http://def.wmo.int/collect/2014" xmlns:iwxxm="http://icao.int/iwxxm/3.0" xmlns:gml="http://www.opengis.net/gml/3.2" xmlns:aixm="http://www.aixm.aero/schema/5.1.1" xmlns:xlink="http://www.w3.org/1999/xlink" gml:id="b1">
+<collect:meteorologicalInformation>
+<iwxxm:TAF gml:id="t1" reportStatus="NORMAL">
+  <iwxxm:issueTime><gml:TimeInstant gml:id="t2"><gml:timePosition>2019-06-07T04:56:00Z</gml:timePosition></gml:TimeInstant></iwxxm:issueTime>
+  <iwxxm:aerodrome><aixm:AirportHeliport gml:id="t3"><aixm:timeSlice><aixm:AirportHeliportTimeSlice gml:id="t4"><gml:validTime/><aixm:interpretation>SNAPSHOT</aixm:interpretation><aixm:designator>URSS</aixm:designator><aixm:locationIndicatorICAO>URSS</aixm:locationIndicatorICAO></aixm:AirportHeliportTimeSlice></aixm:timeSlice></aixm:AirportHeliport></iwxxm:aerodrome>
+  <iwxxm:validPeriod><gml:TimePeriod gml:id="t5"><gml:beginPosition>2019-06-07T06:00:00Z</gml:beginPosition><gml:endPosition>2019-06-08T06:00:00Z</gml:endPosition></gml:TimePeriod></iwxxm:validPeriod>
+  <iwxxm:baseForecast><iwxxm:MeteorologicalAerodromeForecast gml:id="t6" cloudAndVisibilityOK="false">
+    <iwxxm:phenomenonTime xlink:href="#t5"/>
+    <iwxxm:prevailingVisibility uom="m">10000</iwxxm:prevailingVisibility>
+    <iwxxm:surfaceWind><iwxxm:AerodromeSurfaceWindForecast variableWindDirection="false"><iwxxm:meanWindDirection uom="deg">230</iwxxm:meanWindDirection><iwxxm:meanWindSpeed uom="m/s">5</iwxxm:meanWindSpeed></iwxxm:AerodromeSurfaceWindForecast></iwxxm:surfaceWind>
+    <iwxxm:cloud><iwxxm:AerodromeCloudForecast><iwxxm:layer><iwxxm:CloudLayer><iwxxm:amount xlink:href="http://codes.wmo.int/49-2/CloudAmountReportedAtAerodrome/FEW"/><iwxxm:base uom="[ft_i]">4000</iwxxm:base></iwxxm:CloudLayer></iwxxm:layer></iwxxm:AerodromeCloudForecast></iwxxm:cloud>
+  </iwxxm:MeteorologicalAerodromeForecast></iwxxm:baseForecast>
+  <iwxxm:changeForecast><iwxxm:MeteorologicalAerodromeForecast gml:id="t7" changeIndicator="BECOMING" cloudAndVisibilityOK="false">
+    <iwxxm:phenomenonTime><gml:TimePeriod gml:id="t8"><gml:beginPosition>2019-06-07T08:00:00Z</gml:beginPosition><gml:endPosition>2019-06-07T09:00:00Z</gml:endPosition></gml:TimePeriod></iwxxm:phenomenonTime>
+    <iwxxm:surfaceWind><iwxxm:AerodromeSurfaceWindForecast variableWindDirection="false"><iwxxm:meanWindDirection uom="deg">280</iwxxm:meanWindDirection><iwxxm:meanWindSpeed uom="m/s">6</iwxxm:meanWindSpeed><iwxxm:windGustSpeed uom="m/s">11</iwxxm:windGustSpeed></iwxxm:AerodromeSurfaceWindForecast></iwxxm:surfaceWind>
+    <iwxxm:cloud><iwxxm:AerodromeCloudForecast><iwxxm:layer><iwxxm:CloudLayer><iwxxm:amount xlink:href="http://codes.wmo.int/49-2/CloudAmountReportedAtAerodrome/SCT"/><iwxxm:base uom="[ft_i]">3000</iwxxm:base><iwxxm:cloudType xlink:href="http://codes.wmo.int/49-2/SigConvectiveCloudType/CB"/></iwxxm:CloudLayer></iwxxm:layer></iwxxm:AerodromeCloudForecast></iwxxm:cloud>
+  </iwxxm:MeteorologicalAerodromeForecast></iwxxm:changeForecast>
+  <iwxxm:changeForecast><iwxxm:MeteorologicalAerodromeForecast gml:id="t9" changeIndicator="PROBABILITY_30_TEMPORARY_FLUCTUATIONS" cloudAndVisibilityOK="false">
+    <iwxxm:phenomenonTime><gml:TimePeriod gml:id="t10"><gml:beginPosition>2019-06-07T09:00:00Z</gml:beginPosition><gml:endPosition>2019-06-07T17:00:00Z</gml:endPosition></gml:TimePeriod></iwxxm:phenomenonTime>
+    <iwxxm:weather xlink:href="http://codes.wmo.int/306/4678/-TSRA"/>
+  </iwxxm:MeteorologicalAerodromeForecast></iwxxm:changeForecast>
+</iwxxm:TAF>
+</collect:meteorologicalInformation>
+<collect:bulletinIdentifier>A_LTRS01URSS070456.xml</collect:bulletinIdentifier>
+</collect:MeteorologicalBulletin>`
+
+func TestUnmarshalIWXXMMetars(t *testing.T) {
+	Convey("Unmarshal IWXXM METAR should work correctly", t, func() {
+		mr, err := UnmarshalIWXXMMetars([]byte(iwxxmMetarFixture))
+		So(err, ShouldBeNil)
+		So(mr.Data.METAR, ShouldResemble, []METAR{{
+			RawText:         "ULLI 100800Z 23007MPS 9999 FEW040 20/11 Q1022",
+			StationID:       "ULLI",
+			ObservationTime: time.Date(2019, 06, 10, 8, 0, 0, 0, time.UTC),
+			Latitude:        59.8, Longitude: 30.27, TempC: 20, DewpointC: 11, WindDirDegrees: 230, WindSpeedKt: 14, VisibilityStatuteMi: 6.21, AltimInHg: 30.177166,
+			SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}},
+			MetarType:    "METAR", ElevationM: 4}})
+	})
+	Convey("Reported zeros should be told apart from missing values", t, func() {
+		calm := strings.NewReplacer(
+			`<iwxxm:airTemperature uom="Cel">20`, `<iwxxm:airTemperature uom="Cel">0`,
+			`<iwxxm:dewpointTemperature uom="Cel">11</iwxxm:dewpointTemperature>`, ``,
+			`<iwxxm:meanWindDirection uom="deg">230`, `<iwxxm:meanWindDirection uom="deg">0`,
+			`<iwxxm:meanWindSpeed uom="m/s">7`, `<iwxxm:meanWindSpeed uom="m/s">0`,
+		).Replace(iwxxmMetarFixture)
+		mr, err := UnmarshalIWXXMMetars([]byte(calm))
+		So(err, ShouldBeNil)
+		m := mr.Data.METAR[0]
+		So(m.RawText, ShouldEqual, "ULLI 100800Z 00000MPS 9999 FEW040 00/ Q1022")
+		g := groupsOf(&m)
+		So(g.wind, ShouldBeTrue)
+		So(g.temperature, ShouldBeTrue)
+		So(g.dewpoint, ShouldBeFalse)
+	})
+	Convey("Malformed IWXXM should return an error", t, func() {
+		_, err := UnmarshalIWXXMMetars([]byte(`<iwxxm:METAR xmlns:iwxxm="http://icao.int/iwxxm/3.0"><iwxxm:issueTime>`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestUnmarshalIWXXMTafs(t *testing.T) {
+	Convey("Unmarshal IWXXM TAF bulletin should work correctly", t, func() {
+		tr, err := UnmarshalIWXXMTafs([]byte(iwxxmTafFixture))
+		So(err, ShouldBeNil)
+		So(tr.Data.TAF, ShouldHaveLength, 1)
+		taf := tr.Data.TAF[0]
+		So(taf.StationID, ShouldEqual, "URSS")
+		So(taf.ValidTimeFrom, ShouldResemble, time.Date(2019, 06, 7, 6, 0, 0, 0, time.UTC))
+		So(taf.Forecast, ShouldResemble, []Forecast{
+			{FcstTimeFrom: time.Date(2019, 06, 7, 6, 0, 0, 0, time.UTC), FcstTimeTo: time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC),
+				WindDirDegrees: 230, WindSpeedKt: 10, VisibilityStatuteMi: 6.21,
+				SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}}},
+			{FcstTimeFrom: time.Date(2019, 06, 7, 8, 0, 0, 0, time.UTC), FcstTimeTo: time.Date(2019, 06, 8, 6, 0, 0, 0, time.UTC),
+				ChangeIndicator: "BECMG", TimeBecoming: time.Date(2019, 06, 7, 9, 0, 0, 0, time.UTC),
+				WindDirDegrees: 280, WindSpeedKt: 12, WindGustKt: 21,
+				SkyCondition: []SkyCondition{{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}},
+			{FcstTimeFrom: time.Date(2019, 06, 7, 9, 0, 0, 0, time.UTC), FcstTimeTo: time.Date(2019, 06, 7, 17, 0, 0, 0, time.UTC),
+				ChangeIndicator: "TEMPO", Probability: "30", WxString: "-TSRA"},
+		})
+	})
+}
+
+func TestMarshalIWXXM(t *testing.T) {
+	Convey("Encoded METAR should be decoded back to the same values", t, func() {
+		mr, err := UnmarshalIWXXMMetars([]byte(iwxxmMetarFixture))
+		So(err, ShouldBeNil)
+		mr.Data.METAR[0].WxString = "-TSRA"
+		mr.Data.METAR[0].QualityControlFlags.Corrected = true
+		output, err := MarshalIWXXMMetars(mr)
+		So(err, ShouldBeNil)
+		So(string(output), ShouldContainSubstring, `<collect:MeteorologicalBulletin xmlns:collect="http://def.wmo.int/collect/2014" xmlns:iwxxm="http://icao.int/iwxxm/3.0"`)
+		So(string(output), ShouldContainSubstring, `<iwxxm:qnh uom="hPa">1022</iwxxm:qnh>`)
+		decoded, err := UnmarshalIWXXMMetars(output)
+		So(err, ShouldBeNil)
+		// the rebuilt report carries the units of the encoder
+		So(decoded.Data.METAR[0].RawText, ShouldEqual, "ULLI 100800Z 23014KT 9999 -TSRA FEW040 20/11 Q1022")
+		mr.Data.METAR[0].RawText = decoded.Data.METAR[0].RawText
+		So(decoded.Data.METAR, ShouldResemble, mr.Data.METAR)
+	})
+	Convey("Encoded TAF should be decoded back to the same values", t, func() {
+		tr, err := UnmarshalIWXXMTafs([]byte(iwxxmTafFixture))
+		So(err, ShouldBeNil)
+		tr.Data.TAF[0].Forecast[0].Temperature = []Temperature{{ValidTime: time.Date(2019, 06, 7, 12, 0, 0, 0, time.UTC), MaxTempC: "24"}}
+		output, err := MarshalIWXXMTafs(tr)
+		So(err, ShouldBeNil)
+		decoded, err := UnmarshalIWXXMTafs(output)
+		So(err, ShouldBeNil)
+		So(decoded.Data.TAF, ShouldResemble, tr.Data.TAF)
+	})
+	Convey("TAF without a base forecast should be encoded and decoded without one", t, func() {
+		tr, err := UnmarshalIWXXMTafs([]byte(iwxxmTafFixture))
+		So(err, ShouldBeNil)
+		tr.Data.TAF[0].Forecast = tr.Data.TAF[0].Forecast[2:]
+		output, err := MarshalIWXXMTafs(tr)
+		So(err, ShouldBeNil)
+		So(string(output), ShouldNotContainSubstring, "baseForecast")
+		decoded, err := UnmarshalIWXXMTafs(output)
+		So(err, ShouldBeNil)
+		So(decoded.Data.TAF[0].Forecast, ShouldResemble, tr.Data.TAF[0].Forecast)
+	})
+	Convey("Encoding nothing should return an error", t, func() {
+		_, err := MarshalIWXXMMetars(&METARresponse{})
+		So(err, ShouldNotBeNil)
+	})
+}
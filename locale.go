@@ -0,0 +1,230 @@
+package addstogo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Units of plain language descriptions.
+const (
+	UnitKnots                = "kt"
+	UnitMetresPerSecond      = "m/s"
+	UnitKilometresPerHour    = "km/h"
+	UnitStatuteMiles         = "SM"
+	UnitMetres               = "m"
+	UnitFeet                 = "ft"
+	UnitHectopascals         = "hPa"
+	UnitInchesOfMercury      = "inHg"
+	UnitMillimetresOfMercury = "mmHg"
+)
+
+// Units are the preferred units of a locale. An empty Pressure keeps the unit of the report.
+type Units struct {
+	WindSpeed string
+	// Distance is UnitStatuteMiles or UnitMetres, which gives kilometres for long distances.
+	Distance string
+	Height   string
+	Pressure string
+}
+
+// Locale is a language of plain language descriptions with its message catalog and preferred units.
+type Locale struct {
+	Language   string
+	Units      Units
+	TimeFormat string
+	// DecimalMark and ThousandsSeparator format numbers.
+	DecimalMark        string
+	ThousandsSeparator string
+	// Messages translate the English messages, which are used when a translation is missing.
+	// Messages with plural forms hold the forms separated by "|".
+	Messages map[string]string
+	// PluralForm returns the index of the form of a message to use for n.
+	PluralForm func(n int) int
+}
+
+// English is the default locale, with the units of the reports.
+var English = &Locale{
+	Language:           "en",
+	Units:              Units{WindSpeed: UnitKnots, Distance: UnitStatuteMiles, Height: UnitFeet},
+	TimeFormat:         "2 Jan 15:04 MST",
+	DecimalMark:        ".",
+	ThousandsSeparator: ",",
+	PluralForm: func(n int) int {
+		if n == 1 {
+			return 0
+		}
+		return 1
+	},
+}
+
+// Locales are the available locales by language.
+var Locales = map[string]*Locale{"en": English, "ru": Russian}
+
+// LocaleFor returns the locale of a language tag like "ru" or "ru-RU", or English when there is none.
+func LocaleFor(tag string) *Locale {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if l, ok := Locales[tag]; ok {
+		return l
+	}
+	return English
+}
+
+func (l *Locale) text(message string) string {
+	if s, ok := l.Messages[message]; ok {
+		return s
+	}
+	return message
+}
+
+func (l *Locale) sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(l.text(format), args...)
+}
+
+// plural formats n with the form of the message for n.
+func (l *Locale) plural(message string, n int) string {
+	forms := strings.Split(l.text(message), "|")
+	i := 0
+	if l.PluralForm != nil {
+		i = l.PluralForm(n)
+	}
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return fmt.Sprintf(forms[i], n)
+}
+
+// phrase is a text with its English original, so that translations of whole phrases take precedence over their parts.
+type phrase struct {
+	en, local string
+}
+
+func (l *Locale) word(en string) phrase {
+	return phrase{en, l.text(en)}
+}
+
+func (l *Locale) compose(format string, args ...phrase) phrase {
+	en, local := make([]interface{}, len(args)), make([]interface{}, len(args))
+	for i, a := range args {
+		en[i], local[i] = a.en, a.local
+	}
+	p := phrase{en: fmt.Sprintf(format, en...)}
+	if s, ok := l.Messages[p.en]; ok {
+		p.local = s
+	} else {
+		p.local = fmt.Sprintf(l.text(format), local...)
+	}
+	return p
+}
+
+// number formats a value with up to prec decimals and a thousands separator.
+func (l *Locale) number(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, fraction = s[:i], l.DecimalMark+s[i+1:]
+	}
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + l.ThousandsSeparator + whole[i:]
+	}
+	return sign + whole + fraction
+}
+
+func (l *Locale) time(t time.Time) string {
+	return t.UTC().Format(l.TimeFormat)
+}
+
+func (l *Locale) speed(kt int) string {
+	switch l.Units.WindSpeed {
+	case UnitMetresPerSecond:
+		return l.plural("%d metre per second|%d metres per second", int(math.Round(float64(kt)*0.514444)))
+	case UnitKilometresPerHour:
+		return l.plural("%d km/h", int(math.Round(float64(kt)*1.852)))
+	}
+	return l.plural("%d knot|%d knots", kt)
+}
+
+// gust returns the gust speed in the unit of the wind speed, without the unit.
+func (l *Locale) gust(kt int) int {
+	switch l.Units.WindSpeed {
+	case UnitMetresPerSecond:
+		return int(math.Round(float64(kt) * 0.514444))
+	case UnitKilometresPerHour:
+		return int(math.Round(float64(kt) * 1.852))
+	}
+	return kt
+}
+
+func (l *Locale) height(ft int) string {
+	if l.Units.Height == UnitMetres {
+		return l.sprintf("%s m", l.number(math.Round(float64(ft)*0.3048/10)*10, 0))
+	}
+	return l.sprintf("%s ft", l.number(float64(ft), 0))
+}
+
+// visibility formats a visibility reported in metres or in statute miles. 9999 and P6SM are both decoded as 6.21.
+func (l *Locale) visibility(sm float32, metric bool) string {
+	if metric && decimal(sm) == 6.21 {
+		return l.text("visibility 10 km or more")
+	}
+	if l.Units.Distance == UnitMetres {
+		m := decimal(sm) * 1609.344
+		switch {
+		case m < 800:
+			return l.sprintf("visibility %s m", l.number(math.Round(m/50)*50, 0))
+		case m < 5000:
+			return l.sprintf("visibility %s m", l.number(math.Round(m/100)*100, 0))
+		}
+		return l.sprintf("visibility %s km", l.number(math.Round(m/1000), 0))
+	}
+	if !metric && decimal(sm) == 6.21 {
+		return l.text("visibility more than 6 statute miles")
+	}
+	return l.sprintf("visibility %s statute miles (%s km)", l.number(decimal(sm), 2), l.number(decimal(sm)*1.609344, 1))
+}
+
+// pressure formats an altimeter setting, in hectopascals or inches of mercury as reported unless the locale prefers a unit.
+func (l *Locale) pressure(inHg float32, hPaReported bool) string {
+	unit := l.Units.Pressure
+	if unit == "" {
+		unit = UnitInchesOfMercury
+		if hPaReported {
+			unit = UnitHectopascals
+		}
+	}
+	switch unit {
+	case UnitHectopascals:
+		return l.sprintf("QNH %s hPa", strconv.FormatFloat(float64(inHg)/inHgPerHPa, 'f', 0, 64))
+	case UnitMillimetresOfMercury:
+		return l.sprintf("QNH %s mmHg", strconv.FormatFloat(decimal(inHg)*25.4, 'f', 0, 64))
+	}
+	return l.sprintf("altimeter %s inHg", strconv.FormatFloat(decimal(inHg), 'f', 2, 64))
+}
+
+func (l *Locale) celsius(c float64) string {
+	return l.sprintf("%s °C", l.number(c, 1))
+}
+
+// sentence joins the phrases and capitalizes the first letter.
+func (l *Locale) sentence(phrases []string) string {
+	s := strings.Join(phrases, ", ")
+	if s == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:] + "."
+}
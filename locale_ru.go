@@ -0,0 +1,169 @@
+package addstogo
+
+// Russian is the Russian locale, with metric units.
+var Russian = &Locale{
+	Language:           "ru",
+	Units:              Units{WindSpeed: UnitMetresPerSecond, Distance: UnitMetres, Height: UnitMetres, Pressure: UnitHectopascals},
+	TimeFormat:         "02.01 15:04 MST",
+	DecimalMark:        ",",
+	ThousandsSeparator: " ",
+	PluralForm: func(n int) int {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+			return 1
+		}
+		return 2
+	},
+	Messages: map[string]string{
+		// reports
+		"%s %s at %s.":     "%s: %s за %s.",
+		"report":           "сводка",
+		"special report":   "специальная сводка",
+		"automated report": "автоматическая сводка",
+		"corrected %s":     "исправленная %s",
+		"%s %s issued at %s, valid from %s to %s.": "%s: %s, выпущен %s, действует с %s до %s.",
+		"forecast":                      "прогноз",
+		"amended forecast":              "изменённый прогноз",
+		"corrected forecast":            "исправленный прогноз",
+		"From %s":                       "С %s",
+		"Becoming between %s and %s":    "Постепенно между %s и %s",
+		"Temporarily between %s and %s": "Временами между %s и %s",
+		"%s between %s and %s":          "%s между %s и %s",
+		"%s%% probability of temporary changes between %s and %s": "С вероятностью %s%% временами между %s и %s",
+		"%s%% probability between %s and %s":                      "С вероятностью %s%% между %s и %s",
+
+		// wind
+		"wind calm":                                "штиль",
+		"wind variable at %s":                      "ветер переменный %s",
+		"wind from %03d° at %s":                    "ветер %03d° %s",
+		" gusting %d":                              ", порывы до %d",
+		", varying between %03d° and %03d°":        ", направление меняется от %03d° до %03d°",
+		"wind shear at %s from %03d° at %s":        "сдвиг ветра на высоте %s: %03d° %s",
+		"%d knot|%d knots":                         "%d узел|%d узла|%d узлов",
+		"%d metre per second|%d metres per second": "%d м/с",
+		"%d km/h": "%d км/ч",
+
+		// visibility and clouds
+		"visibility 10 km or more":             "видимость 10 км и более",
+		"visibility %s m":                      "видимость %s м",
+		"visibility %s km":                     "видимость %s км",
+		"visibility %s statute miles (%s km)":  "видимость %s уставной мили (%s км)",
+		"visibility more than 6 statute miles": "видимость более 6 уставных миль",
+		"ceiling and visibility OK":            "видимость и облачность в норме (CAVOK)",
+		"sky clear":                            "ясно",
+		"no significant cloud":                 "без существенной облачности",
+		"sky obscured":                         "небо не видно",
+		"few clouds":                           "незначительная облачность",
+		"scattered clouds":                     "рассеянная облачность",
+		"broken clouds":                        "значительная облачность",
+		"overcast":                             "сплошная облачность",
+		"few %s":                               "незначительная %s облачность",
+		"scattered %s":                         "рассеянная %s облачность",
+		"broken %s":                            "значительная %s облачность",
+		"overcast %s":                          "сплошная %s облачность",
+		"cumulonimbus":                         "кучево-дождевая",
+		"towering cumulus":                     "мощно-кучевая",
+		"cumulus":                              "кучевая",
+		"%s at %s":                             "%s на высоте %s",
+		"vertical visibility %s":               "вертикальная видимость %s",
+		"%s ft":                                "%s фт",
+		"%s m":                                 "%s м",
+
+		// weather
+		"no significant weather":          "без особых явлений погоды",
+		"light %s":                        "%s слабой интенсивности",
+		"heavy %s":                        "%s сильной интенсивности",
+		"%s in the vicinity":              "%s в окрестностях",
+		"%s and %s":                       "%s и %s",
+		"thunderstorm":                    "гроза",
+		"thunderstorm with %s":            "гроза, %s",
+		"showers":                         "ливневые осадки",
+		"%s showers":                      "%s ливневого характера",
+		"shallow %s":                      "поземный %s",
+		"patches of %s":                   "%s местами",
+		"partial %s":                      "%s частично",
+		"low drifting %s":                 "%s, позёмок",
+		"blowing %s":                      "%s, низовая метель",
+		"freezing %s":                     "переохлаждённый %s",
+		"shallow":                         "поземный",
+		"patches of":                      "местами",
+		"partial":                         "частично",
+		"low drifting":                    "позёмок",
+		"blowing":                         "низовая метель",
+		"freezing":                        "переохлаждённые осадки",
+		"drizzle":                         "морось",
+		"rain":                            "дождь",
+		"snow":                            "снег",
+		"snow grains":                     "снежные зёрна",
+		"ice crystals":                    "ледяные иглы",
+		"ice pellets":                     "ледяная крупа",
+		"hail":                            "град",
+		"small hail":                      "снежная крупа",
+		"unknown precipitation":           "неопознанные осадки",
+		"mist":                            "дымка",
+		"fog":                             "туман",
+		"smoke":                           "дым",
+		"volcanic ash":                    "вулканический пепел",
+		"widespread dust":                 "пыль",
+		"sand":                            "песок",
+		"haze":                            "мгла",
+		"spray":                           "брызги",
+		"dust whirls":                     "пыльные вихри",
+		"squalls":                         "шквал",
+		"funnel cloud":                    "воронкообразное облако",
+		"tornado or waterspout":           "смерч",
+		"sandstorm":                       "песчаная буря",
+		"duststorm":                       "пыльная буря",
+		"light rain":                      "слабый дождь",
+		"heavy rain":                      "сильный дождь",
+		"light snow":                      "слабый снег",
+		"heavy snow":                      "сильный снег",
+		"light drizzle":                   "слабая морось",
+		"heavy drizzle":                   "сильная морось",
+		"rain and snow":                   "дождь со снегом",
+		"light rain and snow":             "слабый дождь со снегом",
+		"heavy rain and snow":             "сильный дождь со снегом",
+		"rain showers":                    "ливневый дождь",
+		"light rain showers":              "слабый ливневый дождь",
+		"heavy rain showers":              "сильный ливневый дождь",
+		"snow showers":                    "ливневый снег",
+		"light snow showers":              "слабый ливневый снег",
+		"heavy snow showers":              "сильный ливневый снег",
+		"thunderstorm with rain":          "гроза с дождём",
+		"light thunderstorm with rain":    "гроза со слабым дождём",
+		"heavy thunderstorm with rain":    "гроза с сильным дождём",
+		"thunderstorm with hail":          "гроза с градом",
+		"thunderstorm with rain and hail": "гроза с дождём и градом",
+		"thunderstorm with snow":          "гроза со снегом",
+		"freezing rain":                   "переохлаждённый дождь",
+		"light freezing rain":             "слабый переохлаждённый дождь",
+		"freezing drizzle":                "переохлаждённая морось",
+		"light freezing drizzle":          "слабая переохлаждённая морось",
+		"freezing fog":                    "переохлаждённый туман",
+		"low drifting snow":               "позёмок",
+		"blowing snow":                    "низовая метель",
+		"heavy blowing snow":              "сильная низовая метель",
+		"low drifting sand":               "песчаный позёмок",
+		"blowing sand":                    "песчаная буря низовая",
+		"blowing dust":                    "пыльная буря низовая",
+		"shallow fog":                     "поземный туман",
+		"patches of fog":                  "туман местами",
+		"partial fog":                     "туман частично",
+		"thunderstorm in the vicinity":    "гроза в окрестностях",
+
+		// other elements
+		"temperature %s":     "температура %s",
+		"dew point %s":       "точка росы %s",
+		"%s °C":              "%s °C",
+		"QNH %s hPa":         "QNH %s гПа",
+		"QNH %s mmHg":        "QNH %s мм рт. ст.",
+		"altimeter %s inHg":  "QNH %s дюйма рт. ст.",
+		"flight category %s": "категория полётов %s",
+		"turbulence of intensity %s from %s to %s": "турбулентность интенсивности %s от %s до %s",
+		"icing of intensity %s from %s to %s":      "обледенение интенсивности %s от %s до %s",
+		"maximum temperature %s at %s":             "максимальная температура %s в %s",
+		"minimum temperature %s at %s":             "минимальная температура %s в %s",
+	},
+}
@@ -0,0 +1,80 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLocale(t *testing.T) {
+	Convey("Locales should be found by language tag", t, func() {
+		So(LocaleFor("ru-RU"), ShouldEqual, Russian)
+		So(LocaleFor("RU"), ShouldEqual, Russian)
+		So(LocaleFor("de"), ShouldEqual, English)
+	})
+	Convey("A METAR should be described in Russian with metric units", t, func() {
+		m := METAR{
+			RawText:         "URSS 070930Z VRB06G11MPS 9999 -TSRA BKN007 SCT030CB 19/13 Q1012",
+			StationID:       "URSS",
+			ObservationTime: time.Date(2019, 6, 7, 9, 30, 0, 0, time.UTC),
+			WindSpeedKt:     12, WindGustKt: 21, VisibilityStatuteMi: 6.21, WxString: "-TSRA", MetarType: "SPECI",
+			SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 700}, {SkyCover: "SCT", CloudBaseFtAgl: 3000}},
+			TempC:        19, DewpointC: 13, AltimInHg: 29.88,
+		}
+		So(m.DescribeIn(Russian), ShouldEqual, "URSS: специальная сводка за 07.06 09:30 UTC. Ветер переменный 6 м/с, порывы до 11, "+
+			"видимость 10 км и более, гроза со слабым дождём, значительная облачность на высоте 210 м, "+
+			"рассеянная кучево-дождевая облачность на высоте 910 м, температура 19 °C, точка росы 13 °C, QNH 1012 гПа.")
+	})
+	Convey("A TAF should be described in Russian", t, func() {
+		day := time.Date(2019, 6, 7, 0, 0, 0, 0, time.UTC)
+		taf := TAF{
+			RawText:       "TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040 BECMG 0708/0709 28006G11MPS SCT030CB PROB30 TEMPO 0709/0717 -TSRA",
+			StationID:     "URSS",
+			IssueTime:     day.Add(4*time.Hour + 56*time.Minute),
+			ValidTimeFrom: day.Add(6 * time.Hour),
+			ValidTimeTo:   day.Add(30 * time.Hour),
+			Forecast: []Forecast{
+				{FcstTimeFrom: day.Add(6 * time.Hour), FcstTimeTo: day.Add(8 * time.Hour), WindDirDegrees: 230, WindSpeedKt: 10,
+					VisibilityStatuteMi: 6.21, SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}}},
+				{FcstTimeFrom: day.Add(8 * time.Hour), FcstTimeTo: day.Add(17 * time.Hour), TimeBecoming: day.Add(9 * time.Hour),
+					ChangeIndicator: "BECMG", WindDirDegrees: 280, WindSpeedKt: 12, WindGustKt: 21, VisibilityStatuteMi: 6.21,
+					SkyCondition: []SkyCondition{{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}},
+				{FcstTimeFrom: day.Add(9 * time.Hour), FcstTimeTo: day.Add(17 * time.Hour), ChangeIndicator: "TEMPO", Probability: "30",
+					WxString: "-TSRA"},
+			},
+		}
+		So(taf.DescribeIn(Russian), ShouldEqual, "URSS: прогноз, выпущен 07.06 04:56 UTC, действует с 07.06 06:00 UTC до 08.06 06:00 UTC.\n"+
+			"С 07.06 06:00 UTC: ветер 230° 5 м/с, видимость 10 км и более, незначительная облачность на высоте 1 220 м.\n"+
+			"Постепенно между 07.06 08:00 UTC и 07.06 09:00 UTC: ветер 280° 6 м/с, порывы до 11, видимость 10 км и более, "+
+			"рассеянная кучево-дождевая облачность на высоте 910 м.\n"+
+			"С вероятностью 30% временами между 07.06 09:00 UTC и 07.06 17:00 UTC: гроза со слабым дождём.")
+	})
+	Convey("Units should follow the preferences of the locale", t, func() {
+		l := *Russian
+		l.Units.WindSpeed = UnitKnots
+		So(l.speed(21), ShouldEqual, "21 узел")
+		So(l.speed(22), ShouldEqual, "22 узла")
+		So(l.speed(11), ShouldEqual, "11 узлов")
+		So(l.speed(25), ShouldEqual, "25 узлов")
+		l.Units.WindSpeed = UnitKilometresPerHour
+		So(l.speed(10), ShouldEqual, "19 км/ч")
+		So(Russian.visibility(0.5, true), ShouldEqual, "видимость 800 м")
+		So(Russian.visibility(0.93, true), ShouldEqual, "видимость 1 500 м")
+		So(Russian.visibility(3.11, true), ShouldEqual, "видимость 5 км")
+		So(Russian.visibility(0.25, true), ShouldEqual, "видимость 400 м")
+		So(Russian.visibility(6.21, true), ShouldEqual, "видимость 10 км и более")
+		So(Russian.visibility(10, false), ShouldEqual, "видимость 16 км")
+		So(English.visibility(6.21, true), ShouldEqual, "visibility 10 km or more")
+		So(English.visibility(10, false), ShouldEqual, "visibility 10 statute miles (16.1 km)")
+		So(English.visibility(6.21, false), ShouldEqual, "visibility more than 6 statute miles")
+		l.Units.Pressure = UnitMillimetresOfMercury
+		So(l.pressure(29.92, true), ShouldEqual, "QNH 760 мм рт. ст.")
+		So(English.pressure(29.92, true), ShouldEqual, "QNH 1013 hPa")
+		So(Russian.celsius(-0.5), ShouldEqual, "-0,5 °C")
+		So(English.describeWeather("+SHSN BLSN"), ShouldEqual, "heavy snow showers, blowing snow")
+		So(Russian.describeWeather("+SHSN BLSN VCFG"), ShouldEqual, "сильный ливневый снег, низовая метель, туман в окрестностях")
+		So(Russian.describeWeather("-DZ"), ShouldEqual, "слабая морось")
+		So(Russian.describeWeather("+SS"), ShouldEqual, "песчаная буря сильной интенсивности")
+	})
+}
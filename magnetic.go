@@ -0,0 +1,54 @@
+package addstogo
+
+import (
+	"math"
+	"time"
+
+	"github.com/urkk/addstogo/wmm"
+)
+
+// MagneticModels are the models used for the magnetic directions, newest first. A time is taken by
+// the newest model whose epoch it does not precede. Prepend a newer model loaded by wmm.Load
+// when the embedded ones are out of date.
+var MagneticModels = []*wmm.Model{wmm.WMM2025, wmm.WMM2020}
+
+// magneticModel returns the model in effect at the time, the oldest one for earlier times.
+func magneticModel(at time.Time) *wmm.Model {
+	year := wmm.DecimalYear(at)
+	for _, m := range MagneticModels {
+		if year >= m.Epoch {
+			return m
+		}
+	}
+	return MagneticModels[len(MagneticModels)-1]
+}
+
+// Declination returns the magnetic declination in degrees, positive east, at the position,
+// the elevation in metres and the time.
+func Declination(p Positioned, elevationM float64, at time.Time) float64 {
+	pos := p.Position()
+	return magneticModel(at).Declination(pos.Latitude, pos.Longitude, elevationM/1000, at)
+}
+
+// magneticDir converts a true wind direction, returning false for calm and variable winds.
+func magneticDir(dir int, p Positioned, elevationM float32, at time.Time) (int, bool) {
+	if dir == 0 {
+		return 0, false
+	}
+	d := wmm.ToMagnetic(float64(dir), Declination(p, float64(elevationM), at))
+	m := int(math.Round(d))
+	if m == 0 {
+		m = 360
+	}
+	return m, true
+}
+
+// MagneticWindDir returns the wind direction of the observation relative to magnetic north, 1 to 360.
+func (m METAR) MagneticWindDir() (int, bool) {
+	return magneticDir(m.WindDirDegrees, m, m.ElevationM, m.ObservationTime)
+}
+
+// MagneticWindDir returns the wind direction of a forecast period of the TAF relative to magnetic north, 1 to 360.
+func (t TAF) MagneticWindDir(f Forecast) (int, bool) {
+	return magneticDir(f.WindDirDegrees, t, t.ElevationM, f.FcstTimeFrom)
+}
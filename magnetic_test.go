@@ -0,0 +1,38 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMagneticWind(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	Convey("Declination should come from the magnetic model", t, func() {
+		So(Declination(METAR{Latitude: 80, Longitude: 0}, 0, epoch), ShouldAlmostEqual, -1.28, 0.01)
+		So(Declination(METAR{Latitude: -80, Longitude: -120}, 100000, epoch), ShouldAlmostEqual, 68.78, 0.01)
+		So(Declination(METAR{Latitude: 80, Longitude: 0}, 0, epoch.AddDate(5, 0, 0)), ShouldAlmostEqual, 1.28, 0.01)
+		So(Declination(METAR{Latitude: 0, Longitude: 120}, 0, time.Date(2027, 7, 2, 12, 0, 0, 0, time.UTC)), ShouldAlmostEqual, -0.24, 0.01)
+	})
+	Convey("METAR wind directions should convert to magnetic", t, func() {
+		m := METAR{Latitude: 80, Longitude: 0, ObservationTime: epoch, WindDirDegrees: 90, WindSpeedKt: 10}
+		dir, ok := m.MagneticWindDir()
+		So(ok, ShouldBeTrue)
+		So(dir, ShouldEqual, 91)
+		m = METAR{Latitude: -80, Longitude: -120, ObservationTime: epoch, WindDirDegrees: 69, WindSpeedKt: 10}
+		dir, _ = m.MagneticWindDir()
+		So(dir, ShouldEqual, 360)
+		m.WindDirDegrees = 0
+		_, ok = m.MagneticWindDir()
+		So(ok, ShouldBeFalse)
+	})
+	Convey("TAF wind directions should convert at the time of the forecast period", t, func() {
+		taf := TAF{Latitude: -80, Longitude: -120}
+		dir, ok := taf.MagneticWindDir(Forecast{FcstTimeFrom: epoch, WindDirDegrees: 70, WindSpeedKt: 5})
+		So(ok, ShouldBeTrue)
+		So(dir, ShouldEqual, 1)
+		_, ok = taf.MagneticWindDir(Forecast{FcstTimeFrom: epoch, WindSpeedKt: 3})
+		So(ok, ShouldBeFalse)
+	})
+}
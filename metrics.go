@@ -0,0 +1,208 @@
+package addstogo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics is an http.Handler exposing in the Prometheus text format the latest observation
+// of every station in a store, and the fetch and decode counters of instrumented sources.
+type Metrics struct {
+	Store *Store
+
+	mu      sync.Mutex
+	fetches map[fetchKey]*fetchStats
+	now     func() time.Time
+}
+
+type fetchKey struct {
+	source string
+	result string
+}
+
+type fetchStats struct {
+	count    int
+	seconds  float64
+	decoded  int
+	reported string
+}
+
+// NewMetrics returns the metrics of the store.
+func NewMetrics(store *Store) *Metrics {
+	return &Metrics{Store: store, fetches: make(map[fetchKey]*fetchStats), now: time.Now}
+}
+
+func (x *Metrics) observe(source string, started time.Time, err error, decoded int, reported string) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	k := fetchKey{source, result}
+	s, ok := x.fetches[k]
+	if !ok {
+		s = &fetchStats{reported: reported}
+		x.fetches[k] = s
+	}
+	s.count++
+	s.seconds += x.now().Sub(started).Seconds()
+	s.decoded += decoded
+}
+
+// InstrumentMETARSource counts the fetches, their duration and the decoded observations of the source.
+func (x *Metrics) InstrumentMETARSource(name string, source METARSource) METARSource {
+	return func(ctx context.Context) (*METARresponse, error) {
+		started := x.now()
+		r, err := source(ctx)
+		decoded := 0
+		if err == nil {
+			decoded = len(r.Data.METAR)
+		}
+		x.observe(name, started, err, decoded, "metar")
+		return r, err
+	}
+}
+
+// InstrumentTAFSource counts the fetches, their duration and the decoded forecasts of the source.
+func (x *Metrics) InstrumentTAFSource(name string, source TAFSource) TAFSource {
+	return func(ctx context.Context) (*TAFresponse, error) {
+		started := x.now()
+		r, err := source(ctx)
+		decoded := 0
+		if err == nil {
+			decoded = len(r.Data.TAF)
+		}
+		x.observe(name, started, err, decoded, "taf")
+		return r, err
+	}
+}
+
+// decimal converts a decoded value to float64 without the binary noise of float32, e.g. 14.4 rather than 14.399999618530273.
+func decimal(v float32) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+	return f
+}
+
+// promLabel escapes a label value.
+var promLabel = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type promSample struct {
+	labels string
+	value  float64
+}
+
+type promFamily struct {
+	name, help, kind string
+	samples          []promSample
+}
+
+func (f *promFamily) add(value float64, labels ...string) {
+	var b strings.Builder
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, labels[i], promLabel.Replace(labels[i+1]))
+	}
+	f.samples = append(f.samples, promSample{b.String(), value})
+}
+
+func (f *promFamily) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+	for _, s := range f.samples {
+		if s.labels == "" {
+			fmt.Fprintf(w, "%s %g\n", f.name, s.value)
+		} else {
+			fmt.Fprintf(w, "%s{%s} %g\n", f.name, s.labels, s.value)
+		}
+	}
+}
+
+// families returns the current metrics.
+func (x *Metrics) families() []*promFamily {
+	gauge := func(name, help string) *promFamily { return &promFamily{name: name, help: help, kind: "gauge"} }
+	temperature := gauge("addstogo_temperature_celsius", "Air temperature of the latest METAR.")
+	dewpoint := gauge("addstogo_dewpoint_celsius", "Dew point of the latest METAR.")
+	wind := gauge("addstogo_wind_speed_knots", "Wind speed of the latest METAR.")
+	gust := gauge("addstogo_wind_gust_knots", "Wind gust speed of the latest METAR, 0 without gusts.")
+	visibility := gauge("addstogo_visibility_statute_miles", "Visibility of the latest METAR.")
+	altimeter := gauge("addstogo_altimeter_inhg", "Altimeter setting of the latest METAR.")
+	ceiling := gauge("addstogo_ceiling_feet", "Lowest broken or overcast layer or vertical visibility of the latest METAR.")
+	category := gauge("addstogo_flight_category", "Flight category of the latest METAR: 0 LIFR, 1 IFR, 2 MVFR, 3 VFR.")
+	age := gauge("addstogo_observation_age_seconds", "Time since the latest METAR was observed.")
+	families := []*promFamily{temperature, dewpoint, wind, gust, visibility, altimeter, ceiling, category, age}
+
+	if x.Store != nil {
+		now := x.now()
+		for _, m := range x.Store.LatestMETARs() {
+			station := []string{"station", m.StationID}
+			g := groupsOf(&m)
+			if g.temperature {
+				temperature.add(decimal(m.TempC), station...)
+			}
+			if g.dewpoint {
+				dewpoint.add(decimal(m.DewpointC), station...)
+			}
+			if g.wind {
+				wind.add(float64(m.WindSpeedKt), station...)
+				gust.add(float64(m.WindGustKt), station...)
+			}
+			if g.visibility {
+				visibility.add(decimal(m.VisibilityStatuteMi), station...)
+			}
+			if g.altimeter {
+				altimeter.add(decimal(m.AltimInHg), station...)
+			}
+			if c, ok := ceilingFt(&m); ok {
+				ceiling.add(float64(c), station...)
+			}
+			if rank, ok := flightCategoryRank[m.FlightCategory]; ok {
+				category.add(float64(rank), station...)
+			}
+			age.add(now.Sub(m.ObservationTime).Seconds(), station...)
+		}
+	}
+
+	fetches := &promFamily{name: "addstogo_fetches_total", help: "Fetches of the sources by result.", kind: "counter"}
+	duration := &promFamily{name: "addstogo_fetch_duration_seconds_total", help: "Time spent fetching and decoding the sources.", kind: "counter"}
+	decoded := &promFamily{name: "addstogo_decoded_reports_total", help: "Reports decoded from the sources.", kind: "counter"}
+	x.mu.Lock()
+	keys := make([]fetchKey, 0, len(x.fetches))
+	for k := range x.fetches {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].source != keys[j].source {
+			return keys[i].source < keys[j].source
+		}
+		return keys[i].result < keys[j].result
+	})
+	for _, k := range keys {
+		s := x.fetches[k]
+		fetches.add(float64(s.count), "source", k.source, "result", k.result)
+		duration.add(s.seconds, "source", k.source, "result", k.result)
+		if k.result == "success" {
+			decoded.add(float64(s.decoded), "source", k.source, "type", s.reported)
+		}
+	}
+	x.mu.Unlock()
+	return append(families, fetches, duration, decoded)
+}
+
+// ServeHTTP writes the metrics.
+func (x *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	for _, f := range x.families() {
+		f.write(bw)
+	}
+	bw.Flush()
+}
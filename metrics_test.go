@@ -0,0 +1,58 @@
+package addstogo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetrics(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	Convey("Metrics of a store", t, func() {
+		store := NewStore()
+		store.AddMETAR(METAR{StationID: "KSEA", ObservationTime: t0, TempC: 14.4, DewpointC: -1.5, WindSpeedKt: 12, WindGustKt: 22,
+			VisibilityStatuteMi: 10, AltimInHg: 30.02, FlightCategory: "MVFR", SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 1500}, {SkyCover: "BKN", CloudBaseFtAgl: 2800}}})
+		store.AddMETAR(METAR{StationID: `K"Q"`, ObservationTime: t0.Add(-time.Hour)})
+		store.AddMETAR(METAR{StationID: "PABR", ObservationTime: t0, TempC: -5, WindSpeedKt: 5, RawText: "PABR 070900Z 18005KT M05/ RMK AO2"})
+		x := NewMetrics(store)
+		clock := t0
+		x.now = func() time.Time { clock = clock.Add(250 * time.Millisecond); return clock }
+		metars := x.InstrumentMETARSource("adds", func(ctx context.Context) (*METARresponse, error) {
+			return newMETARresponse(make([]METAR, 3)), nil
+		})
+		tafs := x.InstrumentTAFSource("adds-taf", func(ctx context.Context) (*TAFresponse, error) {
+			return nil, errors.New("timeout")
+		})
+		metars(context.Background())
+		metars(context.Background())
+		tafs(context.Background())
+
+		w := httptest.NewRecorder()
+		x.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body := w.Body.String()
+		So(w.Header().Get("Content-Type"), ShouldStartWith, "text/plain; version=0.0.4")
+		So(body, ShouldContainSubstring, "# TYPE addstogo_temperature_celsius gauge\n")
+		So(body, ShouldContainSubstring, "addstogo_temperature_celsius{station=\"KSEA\"} 14.4\n")
+		So(body, ShouldContainSubstring, "addstogo_wind_gust_knots{station=\"KSEA\"} 22\n")
+		So(body, ShouldContainSubstring, "addstogo_altimeter_inhg{station=\"KSEA\"} 30.02\n")
+		So(body, ShouldContainSubstring, "addstogo_ceiling_feet{station=\"KSEA\"} 2800\n")
+		So(body, ShouldContainSubstring, "addstogo_flight_category{station=\"KSEA\"} 2\n")
+		So(body, ShouldNotContainSubstring, "addstogo_dewpoint_celsius{station=\"K\\\"Q\\\"\"}")
+		So(body, ShouldContainSubstring, "addstogo_temperature_celsius{station=\"PABR\"} -5\n")
+		So(body, ShouldContainSubstring, "addstogo_wind_speed_knots{station=\"PABR\"} 5\n")
+		So(body, ShouldNotContainSubstring, "addstogo_dewpoint_celsius{station=\"PABR\"}")
+		So(body, ShouldNotContainSubstring, "addstogo_visibility_statute_miles{station=\"PABR\"}")
+		So(body, ShouldNotContainSubstring, "addstogo_altimeter_inhg{station=\"PABR\"}")
+		So(body, ShouldNotContainSubstring, "addstogo_ceiling_feet{station=\"K\\\"Q\\\"\"}")
+		So(body, ShouldContainSubstring, "addstogo_fetches_total{source=\"adds\",result=\"success\"} 2\n")
+		So(body, ShouldContainSubstring, "addstogo_fetches_total{source=\"adds-taf\",result=\"error\"} 1\n")
+		So(body, ShouldContainSubstring, "addstogo_fetch_duration_seconds_total{source=\"adds\",result=\"success\"} 0.5\n")
+		So(body, ShouldContainSubstring, "addstogo_decoded_reports_total{source=\"adds\",type=\"metar\"} 6\n")
+		So(body, ShouldContainSubstring, "addstogo_observation_age_seconds{station=\"KSEA\"} 1")
+	})
+}
@@ -0,0 +1,122 @@
+package addstogo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// AlertSink delivers alerts.
+type AlertSink interface {
+	Send(ctx context.Context, a Alert) error
+}
+
+// SinkFunc adapts a function to AlertSink.
+type SinkFunc func(ctx context.Context, a Alert) error
+
+// Send calls f(ctx, a).
+func (f SinkFunc) Send(ctx context.Context, a Alert) error {
+	return f(ctx, a)
+}
+
+// WebhookSink posts every alert as JSON to a URL.
+type WebhookSink struct {
+	URL    string
+	Header http.Header
+	// Client is used for the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Retries is the number of additional attempts after a network error or a 429 or 5xx response.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for every next one.
+	Backoff time.Duration
+}
+
+// NewWebhookSink returns a sink posting to the URL with three retries starting at one second.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Retries: 3, Backoff: time.Second}
+}
+
+// Send posts the alert, retrying failed attempts until the retries are used up or the context is done.
+func (w *WebhookSink) Send(ctx context.Context, a Alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	backoff := w.Backoff
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = w.post(ctx, client, payload)
+		if err == nil || !retry || attempt >= w.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one attempt and tells whether a failure is worth retrying.
+func (w *WebhookSink) post(ctx context.Context, client *http.Client, payload []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range w.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		fmt.Errorf("addstogo: webhook %s: %s", w.URL, resp.Status)
+}
+
+// MailSink sends every alert as a plain text email through an SMTP server.
+type MailSink struct {
+	// Addr is the host:port of the server.
+	Addr string
+	// Auth may be nil for servers accepting mail without authentication.
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// Send mails the alert. The context is checked before connecting only, as net/smtp does not take one.
+func (m *MailSink) Send(ctx context.Context, a Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.Addr, m.Auth, m.From, m.To, FormatAlertMail(a, m.From, m.To))
+}
+
+// FormatAlertMail returns the alert as an RFC 5322 message with CRLF line endings.
+func FormatAlertMail(a Alert, from string, to []string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s %s %s\r\n", a.Station, a.Rule, a.State)
+	fmt.Fprintf(&b, "Date: %s\r\n", a.Time.UTC().Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "Rule %s %s at %s, %s.\r\n", a.Rule, a.State, a.Station, a.Time.UTC().Format("2006-01-02 15:04Z"))
+	fmt.Fprintf(&b, "%s\r\n\r\n", a.Reason)
+	fmt.Fprintf(&b, "%s\r\n", a.RawText)
+	return []byte(b.String())
+}
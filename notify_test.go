@@ -0,0 +1,130 @@
+package addstogo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// smtpStandIn accepts one message and returns its data on the channel.
+func smtpStandIn() (string, <-chan string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	data := make(chan string, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var body strings.Builder
+		for inData := false; ; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					data <- body.String()
+					reply("250 OK")
+					continue
+				}
+				body.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return l.Addr().String(), data, nil
+}
+
+func TestNotify(t *testing.T) {
+	alert := Alert{Rule: "gusts", State: AlertRaised, Station: "URSS", Time: time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC),
+		Reason: "gusts 35 kt above 30 kt", RawText: "URSS 070900Z 05015G35KT CAVOK 18/12 Q1012"}
+
+	Convey("Webhook sink", t, func() {
+		var calls int32
+		var received Alert
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Token") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&received)
+		}))
+		defer server.Close()
+		sink := NewWebhookSink(server.URL)
+		sink.Backoff = time.Millisecond
+		sink.Header = http.Header{"X-Token": {"secret"}}
+
+		Convey("should retry and post the alert as JSON", func() {
+			So(sink.Send(context.Background(), alert), ShouldBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 3)
+			So(received, ShouldResemble, alert)
+		})
+		Convey("should give up after the retries", func() {
+			sink.Retries = 1
+			So(sink.Send(context.Background(), alert), ShouldNotBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 2)
+		})
+		Convey("should not retry client errors", func() {
+			sink.Header = nil
+			atomic.StoreInt32(&calls, 2)
+			So(sink.Send(context.Background(), alert), ShouldNotBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 3)
+		})
+		Convey("should stop when the context is done", func() {
+			sink.Backoff = time.Hour
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			So(sink.Send(ctx, alert), ShouldResemble, context.DeadlineExceeded)
+		})
+	})
+	Convey("Mail sink should deliver the raw report", t, func() {
+		addr, data, err := smtpStandIn()
+		So(err, ShouldBeNil)
+		var sink AlertSink = &MailSink{Addr: addr, From: "wx@example.org", To: []string{"ops@example.org"}}
+		So(sink.Send(context.Background(), alert), ShouldBeNil)
+		message := <-data
+		So(message, ShouldContainSubstring, "Subject: URSS gusts raised\r\n")
+		So(message, ShouldContainSubstring, "To: ops@example.org\r\n")
+		So(message, ShouldContainSubstring, "\r\nURSS 070900Z 05015G35KT CAVOK 18/12 Q1012\r\n")
+	})
+	Convey("SinkFunc should adapt a function", t, func() {
+		var got Alert
+		var sink AlertSink = SinkFunc(func(ctx context.Context, a Alert) error { got = a; return nil })
+		So(sink.Send(context.Background(), alert), ShouldBeNil)
+		So(got, ShouldResemble, alert)
+	})
+}
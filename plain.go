@@ -0,0 +1,306 @@
+package addstogo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	wxIntensities = map[string]string{"-": "light %s", "+": "heavy %s"}
+	wxDescriptors = map[string]string{
+		"MI": "shallow %s", "BC": "patches of %s", "PR": "partial %s", "DR": "low drifting %s", "BL": "blowing %s",
+		"SH": "%s showers", "TS": "thunderstorm with %s", "FZ": "freezing %s",
+	}
+	wxPhenomena = map[string]string{
+		"DZ": "drizzle", "RA": "rain", "SN": "snow", "SG": "snow grains", "IC": "ice crystals", "PL": "ice pellets",
+		"GR": "hail", "GS": "small hail", "UP": "unknown precipitation", "BR": "mist", "FG": "fog", "FU": "smoke",
+		"VA": "volcanic ash", "DU": "widespread dust", "SA": "sand", "HZ": "haze", "PY": "spray", "PO": "dust whirls",
+		"SQ": "squalls", "FC": "funnel cloud", "SS": "sandstorm", "DS": "duststorm",
+	}
+	skyCovers = map[string]string{
+		"FEW": "few clouds", "SCT": "scattered clouds", "BKN": "broken clouds", "OVC": "overcast",
+	}
+	skyCoversOfType = map[string]string{
+		"FEW": "few %s", "SCT": "scattered %s", "BKN": "broken %s", "OVC": "overcast %s",
+	}
+	cloudTypes = map[string]string{"CB": "cumulonimbus", "TCU": "towering cumulus", "CU": "cumulus"}
+	cloudGroup = regexp.MustCompile(`^(FEW|SCT|BKN|OVC)(\d{3})(CB|TCU)?$`)
+)
+
+// describeWeather turns present weather groups like "-TSRA BR" into words.
+func (l *Locale) describeWeather(wx string) string {
+	var phrases []string
+	for _, group := range strings.Fields(wx) {
+		if group == "NSW" {
+			phrases = append(phrases, l.text("no significant weather"))
+			continue
+		}
+		intensity, vicinity := "", false
+		switch {
+		case strings.HasPrefix(group, "-"), strings.HasPrefix(group, "+"):
+			intensity, group = group[:1], group[1:]
+		case strings.HasPrefix(group, "VC"):
+			vicinity, group = true, group[2:]
+		}
+		descriptor := ""
+		if len(group) >= 2 {
+			if _, ok := wxDescriptors[group[:2]]; ok {
+				descriptor, group = group[:2], group[2:]
+			}
+		}
+		var what phrase
+		for ; len(group) >= 2; group = group[2:] {
+			name, ok := wxPhenomena[group[:2]]
+			if !ok {
+				name = group[:2]
+			}
+			if group[:2] == "FC" && intensity == "+" {
+				name, intensity = "tornado or waterspout", ""
+			}
+			if what.en == "" {
+				what = l.word(name)
+			} else {
+				what = l.compose("%s and %s", what, l.word(name))
+			}
+		}
+		switch {
+		case descriptor == "":
+		case what.en != "":
+			what = l.compose(wxDescriptors[descriptor], what)
+		case descriptor == "TS":
+			what = l.word("thunderstorm")
+		case descriptor == "SH":
+			what = l.word("showers")
+		default:
+			what = l.word(strings.TrimSpace(strings.Replace(wxDescriptors[descriptor], "%s", "", 1)))
+		}
+		if intensity != "" {
+			what = l.compose(wxIntensities[intensity], what)
+		}
+		if vicinity {
+			what = l.compose("%s in the vicinity", what)
+		}
+		phrases = append(phrases, what.local)
+	}
+	return strings.Join(phrases, ", ")
+}
+
+// describeWind returns the wind in words, or an empty string for a calm wind when calm is false.
+func (l *Locale) describeWind(w Wind, calm bool) string {
+	var s string
+	switch {
+	case w.DirDegrees == 0 && w.SpeedKt == 0:
+		if !calm {
+			return ""
+		}
+		return l.text("wind calm")
+	case w.Variable:
+		s = l.sprintf("wind variable at %s", l.speed(w.SpeedKt))
+	default:
+		s = l.sprintf("wind from %03d° at %s", w.DirDegrees, l.speed(w.SpeedKt))
+	}
+	if w.GustKt > 0 {
+		s += l.sprintf(" gusting %d", l.gust(w.GustKt))
+	}
+	if w.VarFromDeg != w.VarToDeg {
+		s += l.sprintf(", varying between %03d° and %03d°", w.VarFromDeg, w.VarToDeg)
+	}
+	return s
+}
+
+// describeSky returns the cloud layers in words. Cloud types missing from the layers are taken from the raw report.
+func (l *Locale) describeSky(layers []SkyCondition, vertVisFt int, raw string) []string {
+	types := make(map[SkyCondition]string)
+	for _, token := range strings.Fields(raw) {
+		if g := cloudGroup.FindStringSubmatch(token); g != nil && g[3] != "" {
+			ft, _ := strconv.Atoi(g[2])
+			types[SkyCondition{SkyCover: g[1], CloudBaseFtAgl: ft * 100}] = g[3]
+		}
+	}
+	var phrases []string
+	for _, layer := range layers {
+		switch layer.SkyCover {
+		case "SKC", "CLR":
+			phrases = append(phrases, l.text("sky clear"))
+		case "NSC", "NCD":
+			phrases = append(phrases, l.text("no significant cloud"))
+		case "CAVOK":
+			phrases = append(phrases, l.text("ceiling and visibility OK"))
+		case "OVX":
+			phrases = append(phrases, l.text("sky obscured"))
+		default:
+			cloudType := layer.CloudType
+			if cloudType == "" {
+				cloudType = types[SkyCondition{SkyCover: layer.SkyCover, CloudBaseFtAgl: layer.CloudBaseFtAgl}]
+			}
+			cover := layer.SkyCover
+			if name, ok := cloudTypes[cloudType]; ok {
+				cover = l.compose(skyCoversOfType[layer.SkyCover], l.word(name)).local
+			} else if c, ok := skyCovers[layer.SkyCover]; ok {
+				cover = l.text(c)
+			}
+			phrases = append(phrases, l.sprintf("%s at %s", cover, l.height(layer.CloudBaseFtAgl)))
+		}
+	}
+	if vertVisFt > 0 {
+		phrases = append(phrases, l.sprintf("vertical visibility %s", l.height(vertVisFt)))
+	}
+	return phrases
+}
+
+// Describe returns the observation in plain English, e.g. "EGLL report at 1 Jul 12:20 UTC. Wind from 230° at 14 knots, ...".
+func (m METAR) Describe() string {
+	return m.DescribeIn(English)
+}
+
+// DescribeIn returns the observation in plain language in the language and units of the locale.
+func (m METAR) DescribeIn(l *Locale) string {
+	kind := "report"
+	switch {
+	case m.MetarType == "SPECI":
+		kind = "special report"
+	case m.QualityControlFlags.Auto:
+		kind = "automated report"
+	}
+	k := l.word(kind)
+	if m.QualityControlFlags.Corrected {
+		k = l.compose("corrected %s", k)
+	}
+	header := l.sprintf("%s %s at %s.", m.StationID, k.local, l.time(m.ObservationTime))
+	g := groupsOf(&m)
+	var phrases []string
+	if g.wind {
+		phrases = append(phrases, l.describeWind(m.Wind(), true))
+	}
+	cavok := strings.Contains(" "+m.RawText+" ", " CAVOK ")
+	if cavok {
+		phrases = append(phrases, l.text("ceiling and visibility OK"))
+	} else if g.visibility {
+		metric, _ := visibilityInMetres(m.RawText)
+		phrases = append(phrases, l.visibility(m.VisibilityStatuteMi, metric))
+	}
+	if m.WxString != "" {
+		phrases = append(phrases, l.describeWeather(m.WxString))
+	}
+	if !cavok {
+		phrases = append(phrases, l.describeSky(m.SkyCondition, m.VertVisFt, m.RawText)...)
+	}
+	if g.temperature {
+		phrases = append(phrases, l.sprintf("temperature %s", l.celsius(decimal(m.TempC))))
+	}
+	if g.dewpoint {
+		phrases = append(phrases, l.sprintf("dew point %s", l.celsius(decimal(m.DewpointC))))
+	}
+	if g.altimeter {
+		phrases = append(phrases, l.pressure(m.AltimInHg, hectopascalsReported(m.RawText)))
+	}
+	if m.FlightCategory != "" {
+		phrases = append(phrases, l.sprintf("flight category %s", m.FlightCategory))
+	}
+	if body := l.sentence(phrases); body != "" {
+		return header + " " + body
+	}
+	return header
+}
+
+// Describe returns the forecast period in plain English, starting with its change indicator and probability.
+func (f Forecast) Describe() string {
+	return f.DescribeIn(English)
+}
+
+// DescribeIn returns the forecast period in plain language in the language and units of the locale.
+// Without the raw forecast the visibility is taken as reported in metres.
+func (f Forecast) DescribeIn(l *Locale) string {
+	return f.describeIn(l, true)
+}
+
+func (f Forecast) describeIn(l *Locale, metric bool) string {
+	from, to := l.time(f.FcstTimeFrom), l.time(f.FcstTimeTo)
+	var when string
+	switch f.ChangeIndicator {
+	case "", "FM":
+		when = l.sprintf("From %s", from)
+	case "BECMG":
+		if !f.TimeBecoming.IsZero() {
+			to = l.time(f.TimeBecoming)
+		}
+		when = l.sprintf("Becoming between %s and %s", from, to)
+	case "TEMPO":
+		when = l.sprintf("Temporarily between %s and %s", from, to)
+	default:
+		when = l.sprintf("%s between %s and %s", f.ChangeIndicator, from, to)
+	}
+	if f.Probability != "" {
+		if f.ChangeIndicator == "TEMPO" {
+			when = l.sprintf("%s%% probability of temporary changes between %s and %s", f.Probability, from, to)
+		} else {
+			when = l.sprintf("%s%% probability between %s and %s", f.Probability, from, to)
+		}
+	}
+	// calm is only stated for the periods that forecast every element
+	full := f.ChangeIndicator == "" || f.ChangeIndicator == "FM"
+	var phrases []string
+	if w := l.describeWind(f.Wind(), full); w != "" {
+		phrases = append(phrases, w)
+	}
+	if f.WindShearHgtFtAgl > 0 {
+		phrases = append(phrases, l.sprintf("wind shear at %s from %03d° at %s",
+			l.height(f.WindShearHgtFtAgl), f.WindShearDirDegrees, l.speed(f.WindShearSpeedKt)))
+	}
+	cavok := false
+	for _, layer := range f.SkyCondition {
+		cavok = cavok || layer.SkyCover == "CAVOK"
+	}
+	if f.VisibilityStatuteMi > 0 && !cavok {
+		phrases = append(phrases, l.visibility(f.VisibilityStatuteMi, metric))
+	}
+	if f.WxString != "" {
+		phrases = append(phrases, l.describeWeather(f.WxString))
+	}
+	phrases = append(phrases, l.describeSky(f.SkyCondition, f.VertVisFt, "")...)
+	for _, t := range f.TurbulenceCondition {
+		phrases = append(phrases, l.sprintf("turbulence of intensity %s from %s to %s",
+			t.TurbulenceIntensity, l.height(t.TurbulenceMinAltFtAgl), l.height(t.TurbulenceMaxAltFtAgl)))
+	}
+	for _, i := range f.IcingCondition {
+		phrases = append(phrases, l.sprintf("icing of intensity %s from %s to %s",
+			i.IcingIntensity, l.height(i.IcingMinAltFtAgl), l.height(i.IcingMaxAltFtAgl)))
+	}
+	for _, t := range f.Temperature {
+		if c, err := strconv.ParseFloat(t.MaxTempC, 64); err == nil {
+			phrases = append(phrases, l.sprintf("maximum temperature %s at %s", l.celsius(c), l.time(t.ValidTime)))
+		}
+		if c, err := strconv.ParseFloat(t.MinTempC, 64); err == nil {
+			phrases = append(phrases, l.sprintf("minimum temperature %s at %s", l.celsius(c), l.time(t.ValidTime)))
+		}
+	}
+	if len(phrases) == 0 {
+		return when + "."
+	}
+	return when + ": " + strings.Join(phrases, ", ") + "."
+}
+
+// Describe returns the forecast in plain English, a line for the header and a line for every period.
+func (t TAF) Describe() string {
+	return t.DescribeIn(English)
+}
+
+// DescribeIn returns the forecast in plain language in the language and units of the locale.
+func (t TAF) DescribeIn(l *Locale) string {
+	kind := "forecast"
+	switch {
+	case strings.Contains(" "+t.RawText+" ", " AMD "):
+		kind = "amended forecast"
+	case strings.Contains(" "+t.RawText+" ", " COR "):
+		kind = "corrected forecast"
+	}
+	lines := []string{l.sprintf("%s %s issued at %s, valid from %s to %s.",
+		t.StationID, l.text(kind), l.time(t.IssueTime), l.time(t.ValidTimeFrom), l.time(t.ValidTimeTo))}
+	// groups without SM are in metres
+	metric, found := visibilityInMetres(t.RawText)
+	for _, f := range t.Forecast {
+		lines = append(lines, f.describeIn(l, metric || !found))
+	}
+	return strings.Join(lines, "\n")
+}
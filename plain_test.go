@@ -0,0 +1,80 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlainLanguage(t *testing.T) {
+	Convey("Weather groups should read as words", t, func() {
+		So(English.describeWeather("-TSRA BR"), ShouldEqual, "light thunderstorm with rain, mist")
+		So(English.describeWeather("+SHRA VCTS"), ShouldEqual, "heavy rain showers, thunderstorm in the vicinity")
+		So(English.describeWeather("FZFG BCFG -RASN"), ShouldEqual, "freezing fog, patches of fog, light rain and snow")
+		So(English.describeWeather("TSRAGR +FC NSW"), ShouldEqual, "thunderstorm with rain and hail, tornado or waterspout, no significant weather")
+	})
+	Convey("A METAR should be described in sentences", t, func() {
+		m := METAR{
+			RawText:         "UUEE 100800Z VRB06G11MPS 9999 -TSRA BKN007 SCT030CB 15/14 Q1012 NOSIG",
+			StationID:       "UUEE",
+			ObservationTime: time.Date(2019, 7, 10, 8, 0, 0, 0, time.UTC),
+			WindSpeedKt:     12, WindGustKt: 21, VisibilityStatuteMi: 6.21, WxString: "-TSRA",
+			SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 700}, {SkyCover: "SCT", CloudBaseFtAgl: 3000}},
+			TempC:        15, DewpointC: 14, AltimInHg: 29.88, FlightCategory: "IFR",
+		}
+		So(m.Describe(), ShouldEqual, "UUEE report at 10 Jul 08:00 UTC. Wind variable at 12 knots gusting 21, visibility 10 km or more, "+
+			"light thunderstorm with rain, broken clouds at 700 ft, scattered cumulonimbus at 3,000 ft, "+
+			"temperature 15 °C, dew point 14 °C, QNH 1012 hPa, flight category IFR.")
+		m = METAR{
+			RawText:         "KJFK 011251Z 23014G25KT 210V270 3/4SM +SHRA BR OVC008 M01/ A2992",
+			StationID:       "KJFK",
+			ObservationTime: time.Date(2019, 7, 1, 12, 51, 0, 0, time.UTC),
+			MetarType:       "SPECI", WindDirDegrees: 230, WindSpeedKt: 14, WindGustKt: 25, VisibilityStatuteMi: 0.75,
+			WxString: "+SHRA BR", SkyCondition: []SkyCondition{{SkyCover: "OVC", CloudBaseFtAgl: 800}}, TempC: -1, AltimInHg: 29.92,
+		}
+		m.QualityControlFlags.Corrected = true
+		So(m.Describe(), ShouldEqual, "KJFK corrected special report at 1 Jul 12:51 UTC. Wind from 230° at 14 knots gusting 25, "+
+			"varying between 210° and 270°, visibility 0.75 statute miles (1.2 km), heavy rain showers, mist, overcast at 800 ft, "+
+			"temperature -1 °C, altimeter 29.92 inHg.")
+		m = METAR{
+			RawText:         "KBOS 011254Z 27010KT 10SM FEW250 22/12 A3001 RMK AO2 SLP162 QFE745",
+			StationID:       "KBOS",
+			ObservationTime: time.Date(2019, 7, 1, 12, 54, 0, 0, time.UTC),
+			WindDirDegrees:  270, WindSpeedKt: 10, VisibilityStatuteMi: 10, SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 25000}},
+			TempC: 22, DewpointC: 12, AltimInHg: 30.01,
+		}
+		So(m.Describe(), ShouldEqual, "KBOS report at 1 Jul 12:54 UTC. Wind from 270° at 10 knots, visibility 10 statute miles (16.1 km), "+
+			"few clouds at 25,000 ft, temperature 22 °C, dew point 12 °C, altimeter 30.01 inHg.")
+		m = METAR{RawText: "ULLI 100800Z 00000KT CAVOK 20/11 Q1022", StationID: "ULLI",
+			ObservationTime: time.Date(2019, 7, 10, 8, 0, 0, 0, time.UTC), VisibilityStatuteMi: 6.21, TempC: 20, DewpointC: 11, AltimInHg: 30.18}
+		So(m.Describe(), ShouldEqual, "ULLI report at 10 Jul 08:00 UTC. Wind calm, ceiling and visibility OK, "+
+			"temperature 20 °C, dew point 11 °C, QNH 1022 hPa.")
+	})
+	Convey("A TAF should be described period by period", t, func() {
+		day := time.Date(2019, 7, 10, 0, 0, 0, 0, time.UTC)
+		taf := TAF{
+			RawText:       "TAF AMD UUEE 101055Z 1012/1112 ...",
+			StationID:     "UUEE",
+			IssueTime:     day.Add(10*time.Hour + 55*time.Minute),
+			ValidTimeFrom: day.Add(12 * time.Hour),
+			ValidTimeTo:   day.Add(36 * time.Hour),
+			Forecast: []Forecast{
+				{FcstTimeFrom: day.Add(12 * time.Hour), FcstTimeTo: day.Add(36 * time.Hour), WindDirDegrees: 180, WindSpeedKt: 10,
+					VisibilityStatuteMi: 6.21, SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 2000}}},
+				{FcstTimeFrom: day.Add(14 * time.Hour), FcstTimeTo: day.Add(20 * time.Hour), ChangeIndicator: "TEMPO", Probability: "30",
+					WxString: "TSRA", SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 1500, CloudType: "CB"}}},
+				{FcstTimeFrom: day.Add(20 * time.Hour), FcstTimeTo: day.Add(36 * time.Hour), TimeBecoming: day.Add(22 * time.Hour),
+					ChangeIndicator: "BECMG", WindDirDegrees: 270, WindSpeedKt: 5, WindShearHgtFtAgl: 2000, WindShearDirDegrees: 250, WindShearSpeedKt: 40},
+				{FcstTimeFrom: day.Add(30 * time.Hour), FcstTimeTo: day.Add(36 * time.Hour), ChangeIndicator: "FM",
+					VisibilityStatuteMi: 6.21, SkyCondition: []SkyCondition{{SkyCover: "NSC"}},
+					Temperature: []Temperature{{ValidTime: day.Add(33 * time.Hour), MaxTempC: "24"}}},
+			},
+		}
+		So(taf.Describe(), ShouldEqual, "UUEE amended forecast issued at 10 Jul 10:55 UTC, valid from 10 Jul 12:00 UTC to 11 Jul 12:00 UTC.\n"+
+			"From 10 Jul 12:00 UTC: wind from 180° at 10 knots, visibility 10 km or more, broken clouds at 2,000 ft.\n"+
+			"30% probability of temporary changes between 10 Jul 14:00 UTC and 10 Jul 20:00 UTC: thunderstorm with rain, broken cumulonimbus at 1,500 ft.\n"+
+			"Becoming between 10 Jul 20:00 UTC and 10 Jul 22:00 UTC: wind from 270° at 5 knots, wind shear at 2,000 ft from 250° at 40 knots.\n"+
+			"From 11 Jul 06:00 UTC: wind calm, visibility 10 km or more, no significant cloud, maximum temperature 24 °C at 11 Jul 09:00 UTC.")
+	})
+}
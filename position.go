@@ -0,0 +1,43 @@
+package addstogo
+
+import "github.com/urkk/addstogo/geodesy"
+
+// Positioned is implemented by the records carrying station coordinates.
+type Positioned interface {
+	Position() geodesy.Point
+}
+
+// DistanceNM returns the great-circle distance between the positions in nautical miles.
+func DistanceNM(a, b Positioned) float64 {
+	return geodesy.DistanceNM(a.Position(), b.Position())
+}
+
+// DistanceKm returns the great-circle distance between the positions in kilometres.
+func DistanceKm(a, b Positioned) float64 {
+	return geodesy.DistanceKm(a.Position(), b.Position())
+}
+
+// InitialBearing returns the true initial bearing from one position to the other in degrees.
+func InitialBearing(from, to Positioned) float64 {
+	return geodesy.InitialBearing(from.Position(), to.Position())
+}
+
+// Position returns the station coordinates of the report.
+func (m METAR) Position() geodesy.Point {
+	return geodesy.Point{Latitude: float64(m.Latitude), Longitude: float64(m.Longitude)}
+}
+
+// Position returns the station coordinates of the forecast.
+func (t TAF) Position() geodesy.Point {
+	return geodesy.Point{Latitude: float64(t.Latitude), Longitude: float64(t.Longitude)}
+}
+
+// Position returns the station coordinates.
+func (s Station) Position() geodesy.Point {
+	return geodesy.Point{Latitude: float64(s.Latitude), Longitude: float64(s.Longitude)}
+}
+
+// Position returns the waypoint coordinates.
+func (w Waypoint) Position() geodesy.Point {
+	return geodesy.Point{Latitude: w.Latitude, Longitude: w.Longitude}
+}
@@ -0,0 +1,29 @@
+package addstogo
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/urkk/addstogo/geodesy"
+)
+
+func TestPosition(t *testing.T) {
+	Convey("Distances and bearings between records", t, func() {
+		si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+		c := NewCatalog(si.Data.Station)
+		ksea, _ := c.Station("KSEA")
+		kden, _ := c.Station("KDEN")
+		metar := METAR{StationID: "KDEN", Latitude: 39.85, Longitude: -104.65}
+		taf := TAF{StationID: "KSEA", Latitude: 47.45, Longitude: -122.32}
+
+		So(DistanceNM(ksea, kden), ShouldAlmostEqual, 890, 1)
+		So(DistanceKm(ksea, kden), ShouldAlmostEqual, 1648, 1)
+		So(DistanceNM(ksea, metar), ShouldAlmostEqual, DistanceNM(ksea, kden), 1e-9)
+		So(DistanceNM(metar, taf), ShouldAlmostEqual, DistanceNM(ksea, kden), 1e-3)
+		So(DistanceKm(taf, metar), ShouldAlmostEqual, DistanceKm(metar, taf), 1e-9)
+		So(InitialBearing(ksea, kden), ShouldAlmostEqual, 114.4, 0.1)
+		So(InitialBearing(metar, taf), ShouldAlmostEqual, 306.6, 0.1)
+		So(InitialBearing(taf, Waypoint{Latitude: 50, Longitude: float64(taf.Longitude)}), ShouldAlmostEqual, 0, 1e-9)
+		So(ksea.Position(), ShouldResemble, geodesy.Point{Latitude: float64(ksea.Latitude), Longitude: float64(ksea.Longitude)})
+	})
+}
@@ -0,0 +1,90 @@
+package addstogo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The decoded structures hold zero for missing values, which is also a valid reading.
+// metarGroups tells the reported values apart by looking at the groups of the raw report.
+// Without a raw report a value is taken as reported when it is not zero.
+type metarGroups struct {
+	wind, gust, visibility, temperature, dewpoint, altimeter, seaLevelPressure bool
+}
+
+var (
+	windGroup        = regexp.MustCompile(`^(\d{3}|VRB)\d{2,3}(G\d{2,3})?(KT|MPS|KMH)$`)
+	visibilityGroup  = regexp.MustCompile(`^(\d{4}(NDV)?|[MP]?[\d/]+SM|CAVOK)$`)
+	temperatureGroup = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterGroup   = regexp.MustCompile(`^[AQ]\d{4}$`)
+	slpGroup         = regexp.MustCompile(`^SLP\d{3}$`)
+)
+
+func groupsOf(m *METAR) metarGroups {
+	if m.RawText == "" {
+		return metarGroups{
+			wind:             m.WindSpeedKt != 0 || m.WindDirDegrees != 0,
+			gust:             m.WindGustKt != 0,
+			visibility:       m.VisibilityStatuteMi != 0,
+			temperature:      m.TempC != 0,
+			dewpoint:         m.DewpointC != 0,
+			altimeter:        m.AltimInHg != 0,
+			seaLevelPressure: m.SeaLevelPressureMb != 0,
+		}
+	}
+	var g metarGroups
+	remarks := false
+	for _, token := range strings.Fields(m.RawText) {
+		if token == "RMK" {
+			remarks = true
+			continue
+		}
+		if remarks {
+			g.seaLevelPressure = g.seaLevelPressure || slpGroup.MatchString(token)
+			continue
+		}
+		switch {
+		case windGroup.MatchString(token):
+			g.wind = true
+			g.gust = strings.Contains(token, "G")
+		case visibilityGroup.MatchString(token):
+			g.visibility = true
+		case temperatureGroup.MatchString(token):
+			parts := temperatureGroup.FindStringSubmatch(token)
+			g.temperature = true
+			g.dewpoint = parts[2] != ""
+		case altimeterGroup.MatchString(token):
+			g.altimeter = true
+		}
+	}
+	// sea level pressure is also decoded from the Q group outside North America
+	g.seaLevelPressure = g.seaLevelPressure || g.altimeter && m.SeaLevelPressureMb != 0
+	return g
+}
+
+// visibilityInMetres tells whether the first visibility group of the report before the remarks is in metres or CAVOK,
+// and whether there is one.
+func visibilityInMetres(raw string) (metric, found bool) {
+	for _, token := range strings.Fields(raw) {
+		if token == "RMK" {
+			break
+		}
+		if visibilityGroup.MatchString(token) {
+			return !strings.HasSuffix(token, "SM"), true
+		}
+	}
+	return false, false
+}
+
+// hectopascalsReported tells whether the altimeter group of the report before the remarks is a Q group.
+func hectopascalsReported(raw string) bool {
+	for _, token := range strings.Fields(raw) {
+		if token == "RMK" {
+			break
+		}
+		if altimeterGroup.MatchString(token) {
+			return token[0] == 'Q'
+		}
+	}
+	return false
+}
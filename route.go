@@ -0,0 +1,156 @@
+package addstogo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urkk/addstogo/geodesy"
+)
+
+// Waypoint is a point of a route.
+type Waypoint struct {
+	Ident     string
+	Latitude  float64
+	Longitude float64
+}
+
+// RouteStation is a station found along a route, with its weather when attached.
+type RouteStation struct {
+	StationDistance
+	// AlongTrackNM is the distance from the route origin to the abeam point.
+	AlongTrackNM float64
+	METAR        *METAR
+	TAF          *TAF
+}
+
+// ParseRoute resolves a route like "KSEA 45.5/-110.2 KDEN" into waypoints.
+// Identifiers are looked up in the catalog, coordinates are given as lat/lon or lat,lon.
+func ParseRoute(route string, c *Catalog) ([]Waypoint, error) {
+	var waypoints []Waypoint
+	for _, token := range strings.Fields(route) {
+		if sep := strings.IndexAny(token, "/,"); sep > 0 {
+			lat, errLat := strconv.ParseFloat(token[:sep], 64)
+			lon, errLon := strconv.ParseFloat(token[sep+1:], 64)
+			if errLat != nil || errLon != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+				return nil, fmt.Errorf("addstogo: invalid route point %q", token)
+			}
+			waypoints = append(waypoints, Waypoint{Ident: token, Latitude: lat, Longitude: lon})
+			continue
+		}
+		if c == nil {
+			return nil, fmt.Errorf("addstogo: no catalog to look up %q", token)
+		}
+		s, ok := c.Station(token)
+		if !ok {
+			return nil, fmt.Errorf("addstogo: unknown station %q", token)
+		}
+		waypoints = append(waypoints, Waypoint{Ident: s.StationID, Latitude: float64(s.Latitude), Longitude: float64(s.Longitude)})
+	}
+	for i := 1; i < len(waypoints); i++ {
+		a, b := waypoints[i-1], waypoints[i]
+		if geodesy.Angle(a.Position(), b.Position()) > maxLegArc {
+			return nil, fmt.Errorf("addstogo: leg %s-%s is nearly antipodal", a.Ident, b.Ident)
+		}
+	}
+	return waypoints, nil
+}
+
+// maxLegArc is the longest leg in radians. The great circle between nearly antipodal points is undefined.
+const maxLegArc = 179 * math.Pi / 180
+
+// Corridor returns the stations matching every filter within widthNM of the great-circle route,
+// ordered by along-track distance. Legs longer than 179° of arc are left out, as their route is ambiguous;
+// ParseRoute rejects them.
+func (x *StationIndex) Corridor(route []Waypoint, widthNM float64, filters ...StationFilter) []RouteStation {
+	found := make(map[string]*RouteStation)
+	var origin float64
+	for i := 0; i < len(route); i++ {
+		a := route[i].Position()
+		b := a
+		if i+1 < len(route) {
+			b = route[i+1].Position()
+		} else if len(route) > 1 {
+			break
+		}
+		length := geodesy.Angle(a, b)
+		if length > maxLegArc {
+			continue
+		}
+		// long legs are split so that the circle around each piece stays small
+		pieces := int(math.Ceil(length / (math.Pi / 2)))
+		if pieces < 1 {
+			pieces = 1
+		}
+		for k := 0; k < pieces; k++ {
+			from := geodesy.Interpolate(a, b, float64(k)/float64(pieces))
+			to := geodesy.Interpolate(a, b, float64(k+1)/float64(pieces))
+			x.corridorPiece(from, to, origin+length*float64(k)/float64(pieces)*EarthRadiusNM, widthNM, found, filters)
+		}
+		origin += length * EarthRadiusNM
+	}
+	result := make([]RouteStation, 0, len(found))
+	for _, rs := range found {
+		result = append(result, *rs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AlongTrackNM != result[j].AlongTrackNM {
+			return result[i].AlongTrackNM < result[j].AlongTrackNM
+		}
+		return result[i].StationID < result[j].StationID
+	})
+	return result
+}
+
+// corridorPiece adds the stations within widthNM of the great-circle segment a-b, starting origin nautical miles along the route.
+func (x *StationIndex) corridorPiece(a, b geodesy.Point, origin, widthNM float64, found map[string]*RouteStation, filters []StationFilter) {
+	// every point of the corridor lies within this circle around the segment midpoint
+	mid := geodesy.Midpoint(a, b)
+	for _, candidate := range x.WithinRadius(mid.Latitude, mid.Longitude, geodesy.DistanceNM(a, b)/2+widthNM, filters...) {
+		crossTrack, alongTrack := geodesy.SegmentDistance(a, b, candidate.Position())
+		distance := crossTrack * EarthRadiusNM
+		if distance > widthNM {
+			continue
+		}
+		if rs, ok := found[candidate.StationID]; ok && rs.DistanceNM <= distance {
+			continue
+		}
+		candidate.DistanceNM = distance
+		found[candidate.StationID] = &RouteStation{StationDistance: candidate, AlongTrackNM: origin + alongTrack*EarthRadiusNM}
+	}
+}
+
+// AttachWeather sets for every station its latest METAR observed no later than at
+// and the most recently issued TAF valid at that time. A zero time means now.
+func AttachWeather(stations []RouteStation, metars []METAR, tafs []TAF, at time.Time) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	index := make(map[string]int, len(stations))
+	for i := range stations {
+		index[stations[i].StationID] = i
+	}
+	for i := range metars {
+		m := &metars[i]
+		j, ok := index[m.StationID]
+		if !ok || m.ObservationTime.After(at) {
+			continue
+		}
+		if current := stations[j].METAR; current == nil || m.ObservationTime.After(current.ObservationTime) {
+			stations[j].METAR = m
+		}
+	}
+	for i := range tafs {
+		t := &tafs[i]
+		j, ok := index[t.StationID]
+		if !ok || at.Before(t.ValidTimeFrom) || !at.Before(t.ValidTimeTo) {
+			continue
+		}
+		if current := stations[j].TAF; current == nil || t.IssueTime.After(current.IssueTime) {
+			stations[j].TAF = t
+		}
+	}
+}
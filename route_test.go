@@ -0,0 +1,114 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/urkk/addstogo/geodesy"
+)
+
+func TestParseRoute(t *testing.T) {
+	Convey("Routes should be resolved through the catalog", t, func() {
+		si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+		c := NewCatalog(si.Data.Station)
+		route, err := ParseRoute("ksea 45.5/-110.2 KDEN 40,-100", c)
+		So(err, ShouldBeNil)
+		So(route, ShouldResemble, []Waypoint{
+			{Ident: "KSEA", Latitude: 47.45000076293945, Longitude: -122.31999969482422},
+			{Ident: "45.5/-110.2", Latitude: 45.5, Longitude: -110.2},
+			{Ident: "KDEN", Latitude: 39.849998474121094, Longitude: -104.6500015258789},
+			{Ident: "40,-100", Latitude: 40, Longitude: -100},
+		})
+		_, err = ParseRoute("KSEA ZZZZ", c)
+		So(err, ShouldNotBeNil)
+		_, err = ParseRoute("95/10", c)
+		So(err, ShouldNotBeNil)
+		_, err = ParseRoute("0/0 0.5/179.9", c)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestCorridor(t *testing.T) {
+	Convey("Stations along a route", t, func() {
+		si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+		stations := append(si.Data.Station,
+			Station{StationID: "KBIL", Latitude: 45.8, Longitude: -108.53},
+			Station{StationID: "KBOI", Latitude: 43.57, Longitude: -116.22},
+			Station{StationID: "KSLC", Latitude: 40.78, Longitude: -111.97})
+		x := NewStationIndex(stations)
+		route := []Waypoint{{Ident: "KSEA", Latitude: 47.45, Longitude: -122.32}, {Ident: "KDEN", Latitude: 39.85, Longitude: -104.65}}
+
+		Convey("should be ordered by along-track distance", func() {
+			corridor := x.Corridor(route, 100)
+			ids := make([]string, 0, len(corridor))
+			for _, rs := range corridor {
+				ids = append(ids, rs.StationID)
+			}
+			So(ids, ShouldResemble, []string{"KSEA", "KBOI", "KDEN"})
+			So(corridor[0].AlongTrackNM, ShouldAlmostEqual, 0, 0.01)
+			So(corridor[1].DistanceNM, ShouldAlmostEqual, 93, 1)
+			So(corridor[2].AlongTrackNM, ShouldAlmostEqual, 890, 1)
+		})
+		Convey("should widen with the corridor", func() {
+			So(len(x.Corridor(route, 200)), ShouldEqual, 5)
+		})
+		Convey("should follow every leg", func() {
+			dogleg := []Waypoint{route[0], {Ident: "KBIL", Latitude: 45.8, Longitude: -108.53}, route[1]}
+			corridor := x.Corridor(dogleg, 10)
+			So(len(corridor), ShouldEqual, 3)
+			So(corridor[1].StationID, ShouldEqual, "KBIL")
+			So(corridor[2].AlongTrackNM, ShouldBeGreaterThan, 890)
+		})
+		Convey("should cover long legs and skip nearly antipodal ones", func() {
+			x := NewStationIndex([]Station{{StationID: "EQ85", Latitude: 0, Longitude: 85}, {StationID: "EQ160", Latitude: 0, Longitude: 160}})
+			corridor := x.Corridor([]Waypoint{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 170}}, 10)
+			So(len(corridor), ShouldEqual, 2)
+			So(corridor[0].AlongTrackNM, ShouldAlmostEqual, 85*60, 10)
+			So(corridor[1].AlongTrackNM, ShouldAlmostEqual, 160*60, 10)
+			So(x.Corridor([]Waypoint{{Latitude: 0, Longitude: 0}, {Latitude: 0.5, Longitude: 179.9}}, 10), ShouldBeEmpty)
+		})
+		Convey("should agree with a linear scan", func() {
+			stations := randomStations(3000)
+			x := NewStationIndex(stations)
+			route := []Waypoint{{Latitude: 60, Longitude: 170}, {Latitude: 50, Longitude: -150}, {Latitude: -10, Longitude: -140}}
+			expected := map[string]bool{}
+			for _, s := range stations {
+				for i := 0; i+1 < len(route); i++ {
+					d, _ := geodesy.SegmentDistance(route[i].Position(), route[i+1].Position(), s.Position())
+					if d*EarthRadiusNM <= 150 {
+						expected[s.StationID] = true
+					}
+				}
+			}
+			actual := map[string]bool{}
+			for _, rs := range x.Corridor(route, 150) {
+				actual[rs.StationID] = true
+			}
+			So(actual, ShouldResemble, expected)
+		})
+	})
+}
+
+func TestAttachWeather(t *testing.T) {
+	Convey("Latest METAR and current TAF should be attached", t, func() {
+		at := time.Date(2019, 6, 7, 10, 0, 0, 0, time.UTC)
+		stations := []RouteStation{{StationDistance: StationDistance{Station: Station{StationID: "URSS"}}}, {StationDistance: StationDistance{Station: Station{StationID: "ULLI"}}}}
+		metars := []METAR{
+			{StationID: "URSS", ObservationTime: at.Add(-time.Hour), RawText: "old"},
+			{StationID: "URSS", ObservationTime: at.Add(-30 * time.Minute), RawText: "latest"},
+			{StationID: "URSS", ObservationTime: at.Add(30 * time.Minute), RawText: "future"},
+			{StationID: "UUEE", ObservationTime: at},
+		}
+		tafs := []TAF{
+			{StationID: "URSS", IssueTime: at.Add(-6 * time.Hour), ValidTimeFrom: at.Add(-5 * time.Hour), ValidTimeTo: at.Add(19 * time.Hour), RawText: "old"},
+			{StationID: "URSS", IssueTime: at.Add(-3 * time.Hour), ValidTimeFrom: at.Add(-2 * time.Hour), ValidTimeTo: at.Add(22 * time.Hour), RawText: "current"},
+			{StationID: "URSS", IssueTime: at.Add(-time.Hour), ValidTimeFrom: at.Add(time.Hour), ValidTimeTo: at.Add(25 * time.Hour), RawText: "not yet valid"},
+		}
+		AttachWeather(stations, metars, tafs, at)
+		So(stations[0].METAR.RawText, ShouldEqual, "latest")
+		So(stations[0].TAF.RawText, ShouldEqual, "current")
+		So(stations[1].METAR, ShouldBeNil)
+		So(stations[1].TAF, ShouldBeNil)
+	})
+}
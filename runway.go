@@ -0,0 +1,146 @@
+package addstogo
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urkk/addstogo/wmm"
+)
+
+// Runway is a runway direction. The heading must use the reference of the wind, which is true north in METARs and TAFs.
+type Runway struct {
+	Ident      string
+	HeadingDeg float64
+}
+
+// ParseRunways turns designators like "09/27 16L/34R" into runway directions. The designator gives the magnetic
+// heading, ten times the number, which is converted to true with the declination in degrees, east positive,
+// e.g. from Declination. Winds converted to magnetic go with a zero declination, which keeps the magnetic headings.
+func ParseRunways(designators string, declination float64) ([]Runway, error) {
+	var runways []Runway
+	for _, pair := range strings.Fields(designators) {
+		for _, ident := range strings.Split(pair, "/") {
+			number := strings.TrimRight(ident, "LCR")
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 1 || n > 36 || len(ident)-len(number) > 1 {
+				return nil, fmt.Errorf("addstogo: invalid runway %q", ident)
+			}
+			runways = append(runways, Runway{Ident: ident, HeadingDeg: wmm.ToTrue(float64(n*10), declination)})
+		}
+	}
+	return runways, nil
+}
+
+// Wind is a reported or forecast surface wind.
+type Wind struct {
+	DirDegrees int
+	SpeedKt    int
+	GustKt     int
+	// Variable is set for VRB winds, which may blow from any direction.
+	Variable bool
+	// VarFromDeg and VarToDeg bound the direction when it varies, as in 210V270, and are equal otherwise.
+	VarFromDeg, VarToDeg int
+}
+
+var windVariationGroup = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+
+func newWind(dir, speed, gust int) Wind {
+	return Wind{DirDegrees: dir, SpeedKt: speed, GustKt: gust, Variable: dir == 0 && speed > 0, VarFromDeg: dir, VarToDeg: dir}
+}
+
+// Wind returns the wind of the observation, with the direction variation of the raw report.
+func (m METAR) Wind() Wind {
+	w := newWind(m.WindDirDegrees, m.WindSpeedKt, m.WindGustKt)
+	for _, token := range strings.Fields(m.RawText) {
+		if token == "RMK" {
+			break
+		}
+		if g := windVariationGroup.FindStringSubmatch(token); g != nil {
+			w.VarFromDeg, _ = strconv.Atoi(g[1])
+			w.VarToDeg, _ = strconv.Atoi(g[2])
+		}
+	}
+	return w
+}
+
+// Wind returns the wind of the forecast period.
+func (f Forecast) Wind() Wind {
+	return newWind(f.WindDirDegrees, f.WindSpeedKt, f.WindGustKt)
+}
+
+// RunwayWind is the wind along and across a runway.
+type RunwayWind struct {
+	Runway
+	// HeadwindKt is negative for a tailwind.
+	HeadwindKt float64
+	// CrosswindKt is positive for a wind from the right.
+	CrosswindKt     float64
+	GustHeadwindKt  float64
+	GustCrosswindKt float64
+	// MaxCrosswindKt and MaxTailwindKt are the worst cases over the gusts and the directions the wind may blow from.
+	MaxCrosswindKt float64
+	MaxTailwindKt  float64
+}
+
+func components(heading, dir, speed float64) (head, cross float64) {
+	a := (dir - heading) * math.Pi / 180
+	return speed * math.Cos(a), speed * math.Sin(a)
+}
+
+// Components returns the wind components for the runway.
+func (w Wind) Components(r Runway) RunwayWind {
+	rw := RunwayWind{Runway: r}
+	speed, gust := float64(w.SpeedKt), float64(w.GustKt)
+	peak := math.Max(speed, gust)
+	if w.Variable {
+		rw.MaxCrosswindKt, rw.MaxTailwindKt = peak, peak
+		return rw
+	}
+	rw.HeadwindKt, rw.CrosswindKt = components(r.HeadingDeg, float64(w.DirDegrees), speed)
+	if gust > 0 {
+		rw.GustHeadwindKt, rw.GustCrosswindKt = components(r.HeadingDeg, float64(w.DirDegrees), gust)
+	}
+	// walk the arc the wind varies over, clockwise from VarFromDeg
+	span := (w.VarToDeg - w.VarFromDeg + 360) % 360
+	for d := 0; d <= span; d++ {
+		head, cross := components(r.HeadingDeg, float64(w.VarFromDeg+d), peak)
+		rw.MaxCrosswindKt = math.Max(rw.MaxCrosswindKt, math.Abs(cross))
+		rw.MaxTailwindKt = math.Max(rw.MaxTailwindKt, -head)
+	}
+	return rw
+}
+
+// RunwayLimits are the largest components acceptable for operations.
+type RunwayLimits struct {
+	CrosswindKt float64
+	TailwindKt  float64
+}
+
+// Exceeds tells whether the worst case components of the runway exceed the limits.
+func (l RunwayLimits) Exceeds(rw RunwayWind) bool {
+	return rw.MaxCrosswindKt > l.CrosswindKt+1e-9 || rw.MaxTailwindKt > l.TailwindKt+1e-9
+}
+
+// RankRunways returns the components for every runway, best first: runways within the limits
+// before the others, then by headwind, then by the worst crosswind.
+func RankRunways(runways []Runway, w Wind, limits RunwayLimits) []RunwayWind {
+	ranked := make([]RunwayWind, len(runways))
+	for i, r := range runways {
+		ranked[i] = w.Components(r)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if ea, eb := limits.Exceeds(a), limits.Exceeds(b); ea != eb {
+			return eb
+		}
+		if math.Abs(a.HeadwindKt-b.HeadwindKt) > 1e-9 {
+			return a.HeadwindKt > b.HeadwindKt
+		}
+		return a.MaxCrosswindKt < b.MaxCrosswindKt
+	})
+	return ranked
+}
@@ -0,0 +1,80 @@
+package addstogo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunwayWind(t *testing.T) {
+	Convey("Runway designators", t, func() {
+		runways, err := ParseRunways("09/27 16L/34R", 0)
+		So(err, ShouldBeNil)
+		So(runways, ShouldResemble, []Runway{{"09", 90}, {"27", 270}, {"16L", 160}, {"34R", 340}})
+		_, err = ParseRunways("09/37", 0)
+		So(err, ShouldNotBeNil)
+		_, err = ParseRunways("09LL", 0)
+		So(err, ShouldNotBeNil)
+	})
+	Convey("Designator headings should be converted to true with the declination", t, func() {
+		iqaluit := Station{StationID: "CYFB", Latitude: 63.76, Longitude: -68.56}
+		declination := Declination(iqaluit, 34, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+		So(declination, ShouldAlmostEqual, -26.8, 0.1)
+		runways, err := ParseRunways("16/34", declination)
+		So(err, ShouldBeNil)
+		So(runways[0].HeadingDeg, ShouldAlmostEqual, 160+declination, 1e-9)
+		So(runways[1].HeadingDeg, ShouldAlmostEqual, 340+declination, 1e-9)
+		// a METAR wind straight down the runway is a true direction
+		wind := newWind(133, 20, 0)
+		So(math.Abs(wind.Components(runways[0]).CrosswindKt), ShouldBeLessThan, 0.2)
+		magnetic, _ := ParseRunways("16/34", 0)
+		So(math.Abs(wind.Components(magnetic[0]).CrosswindKt), ShouldBeGreaterThan, 8)
+		north, _ := ParseRunways("36", 15)
+		So(north, ShouldResemble, []Runway{{"36", 15}})
+	})
+	Convey("Wind components", t, func() {
+		Convey("should split the wind along and across the runway", func() {
+			rw := newWind(120, 20, 30).Components(Runway{"09", 90})
+			So(rw.HeadwindKt, ShouldAlmostEqual, 10*math.Sqrt(3), 1e-9)
+			So(rw.CrosswindKt, ShouldAlmostEqual, 10, 1e-9)
+			So(rw.GustHeadwindKt, ShouldAlmostEqual, 15*math.Sqrt(3), 1e-9)
+			So(rw.GustCrosswindKt, ShouldAlmostEqual, 15, 1e-9)
+			So(rw.MaxCrosswindKt, ShouldAlmostEqual, 15, 1e-9)
+			So(rw.MaxTailwindKt, ShouldEqual, 0)
+			rw = newWind(60, 20, 0).Components(Runway{"27", 270})
+			So(rw.HeadwindKt, ShouldAlmostEqual, -10*math.Sqrt(3), 1e-9)
+			So(rw.CrosswindKt, ShouldAlmostEqual, 10, 1e-9)
+			So(rw.MaxTailwindKt, ShouldAlmostEqual, 10*math.Sqrt(3), 1e-9)
+		})
+		Convey("should take the worst case of variable winds", func() {
+			w := METAR{RawText: "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022", WindDirDegrees: 230, WindSpeedKt: 14}.Wind()
+			So(w.VarFromDeg, ShouldEqual, 210)
+			So(w.VarToDeg, ShouldEqual, 270)
+			rw := w.Components(Runway{"28", 280})
+			So(rw.MaxCrosswindKt, ShouldAlmostEqual, 14*math.Sin(70*math.Pi/180), 1e-9)
+			rw = w.Components(Runway{"10", 100})
+			So(rw.MaxTailwindKt, ShouldAlmostEqual, 14*math.Cos(10*math.Pi/180), 1e-9)
+			So(w.Components(Runway{"18", 180}).MaxCrosswindKt, ShouldAlmostEqual, 14, 1e-9)
+
+			vrb := Forecast{WindDirDegrees: 0, WindSpeedKt: 6, WindGustKt: 12}.Wind()
+			So(vrb.Variable, ShouldBeTrue)
+			rw = vrb.Components(Runway{"06", 60})
+			So(rw.HeadwindKt, ShouldEqual, 0)
+			So(rw.MaxCrosswindKt, ShouldEqual, 12)
+			So(rw.MaxTailwindKt, ShouldEqual, 12)
+			So(Forecast{}.Wind().Components(Runway{"06", 60}), ShouldResemble, RunwayWind{Runway: Runway{"06", 60}})
+		})
+	})
+	Convey("Runways should be ranked within the limits first", t, func() {
+		runways, _ := ParseRunways("06/24 13/31", 0)
+		ranked := RankRunways(runways, newWind(100, 25, 35), RunwayLimits{CrosswindKt: 20, TailwindKt: 10})
+		idents := make([]string, len(ranked))
+		for i, rw := range ranked {
+			idents[i] = rw.Ident
+		}
+		So(idents, ShouldResemble, []string{"13", "06", "24", "31"})
+		So(RunwayLimits{CrosswindKt: 20, TailwindKt: 10}.Exceeds(ranked[1]), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,274 @@
+package addstogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WeatherSource supplies the reports served by Server.
+type WeatherSource interface {
+	// METARs returns the observations of the station made in [from, to), oldest first.
+	METARs(station string, from, to time.Time) ([]METAR, error)
+	// TAFs returns the forecasts of the station issued in [from, to), oldest first.
+	TAFs(station string, from, to time.Time) ([]TAF, error)
+}
+
+type storeSource struct{ s *Store }
+
+func (x storeSource) METARs(station string, from, to time.Time) ([]METAR, error) {
+	return x.s.METARs(station, from, to), nil
+}
+
+func (x storeSource) TAFs(station string, from, to time.Time) ([]TAF, error) {
+	return x.s.TAFs(station, from, to), nil
+}
+
+// StoreSource serves the reports kept in memory, e.g. filled by a watcher or from a cache file.
+func StoreSource(s *Store) WeatherSource {
+	return storeSource{s}
+}
+
+type archiveSource struct{ a *Archive }
+
+func (x archiveSource) METARs(station string, from, to time.Time) ([]METAR, error) {
+	r, err := x.a.METARs(station, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return r.Data.METAR, nil
+}
+
+func (x archiveSource) TAFs(station string, from, to time.Time) ([]TAF, error) {
+	r, err := x.a.TAFs(station, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return r.Data.TAF, nil
+}
+
+// ArchiveSource serves the reports of the on-disk archive.
+func ArchiveSource(a *Archive) WeatherSource {
+	return archiveSource{a}
+}
+
+// Server is an http.Handler serving decoded reports and stations as JSON:
+//
+//	/metar/{icao}?hours=N   latest observation, or all of the last N hours
+//	/taf/{icao}?hours=N     latest forecast, or all issued in the last N hours
+//	/stations?bbox=minLat,minLon,maxLat,maxLon&country=CC
+//	/nearest?lat=&lon=&n=5
+type Server struct {
+	Source WeatherSource
+	// MaxAge is sent in the Cache-Control header of the reports.
+	MaxAge time.Duration
+	// Lookback is how far back the latest report is looked for.
+	Lookback time.Duration
+
+	mu      sync.Mutex
+	catalog *Catalog
+	index   *StationIndex
+	mux     *http.ServeMux
+	now     func() time.Time
+}
+
+// NewServer returns a server over the source. The catalog may be nil, leaving the station endpoints empty.
+func NewServer(source WeatherSource, c *Catalog) *Server {
+	s := &Server{
+		Source:   source,
+		MaxAge:   time.Minute,
+		Lookback: 36 * time.Hour,
+		mux:      http.NewServeMux(),
+		now:      time.Now,
+	}
+	s.mux.HandleFunc("/metar/", s.serveMETAR)
+	s.mux.HandleFunc("/taf/", s.serveTAF)
+	s.mux.HandleFunc("/stations", s.serveStations)
+	s.mux.HandleFunc("/nearest", s.serveNearest)
+	s.SetCatalog(c)
+	return s
+}
+
+// SetCatalog replaces the catalog of the station endpoints, which may be nil, while serving.
+func (s *Server) SetCatalog(c *Catalog) {
+	if c == nil {
+		c = NewCatalog(nil)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog, s.index = c, nil
+}
+
+// stations returns the catalog and its spatial index, built on first use.
+func (s *Server) stations() (*Catalog, *StationIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil {
+		s.index = NewStationIndex(s.catalog.Stations())
+	}
+	return s.catalog, s.index
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// window returns the time range asked by the hours parameter and whether all of it was asked for.
+func (s *Server) window(r *http.Request) (from, to time.Time, all bool, ok bool) {
+	now := s.now()
+	to = now.Add(time.Hour)
+	if v := r.URL.Query().Get("hours"); v != "" {
+		hours, err := strconv.ParseFloat(v, 64)
+		if err != nil || hours <= 0 {
+			return from, to, false, false
+		}
+		return now.Add(-time.Duration(hours * float64(time.Hour))), to, true, true
+	}
+	return now.Add(-s.Lookback), to, false, true
+}
+
+func (s *Server) serveMETAR(w http.ResponseWriter, r *http.Request) {
+	station := strings.ToUpper(strings.TrimPrefix(r.URL.Path, "/metar/"))
+	from, to, all, ok := s.window(r)
+	if station == "" || !ok {
+		http.Error(w, "station and positive hours expected", http.StatusBadRequest)
+		return
+	}
+	metars, err := s.Source.METARs(station, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(metars) == 0 {
+		http.Error(w, "no METAR for "+station, http.StatusNotFound)
+		return
+	}
+	if !all {
+		metars = metars[len(metars)-1:]
+	}
+	s.writeJSON(w, r, newMETARresponse(metars), metars[len(metars)-1].ObservationTime, s.MaxAge)
+}
+
+func (s *Server) serveTAF(w http.ResponseWriter, r *http.Request) {
+	station := strings.ToUpper(strings.TrimPrefix(r.URL.Path, "/taf/"))
+	from, to, all, ok := s.window(r)
+	if station == "" || !ok {
+		http.Error(w, "station and positive hours expected", http.StatusBadRequest)
+		return
+	}
+	tafs, err := s.Source.TAFs(station, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(tafs) == 0 {
+		http.Error(w, "no TAF for "+station, http.StatusNotFound)
+		return
+	}
+	if !all {
+		tafs = tafs[len(tafs)-1:]
+	}
+	s.writeJSON(w, r, newTAFresponse(tafs), tafs[len(tafs)-1].IssueTime, s.MaxAge)
+}
+
+func (s *Server) serveStations(w http.ResponseWriter, r *http.Request) {
+	var filters []StationFilter
+	if country := r.URL.Query().Get("country"); country != "" {
+		filters = append(filters, InCountry(country))
+	}
+	if bbox := r.URL.Query().Get("bbox"); bbox != "" {
+		box, ok := parseFloats(bbox, 4)
+		if !ok {
+			http.Error(w, "bbox expected as minLat,minLon,maxLat,maxLon", http.StatusBadRequest)
+			return
+		}
+		filters = append(filters, inBox(box[0], box[1], box[2], box[3]))
+	}
+	c, _ := s.stations()
+	s.writeJSON(w, r, newStationsInfoResponse(c.Stations(filters...)), time.Time{}, 24*time.Hour)
+}
+
+func (s *Server) serveNearest(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+	lon, errLon := strconv.ParseFloat(q.Get("lon"), 64)
+	n := 5
+	if v := q.Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			errLat = strconv.ErrSyntax
+		}
+	}
+	if errLat != nil || errLon != nil {
+		http.Error(w, "lat, lon and positive n expected", http.StatusBadRequest)
+		return
+	}
+	var filters []StationFilter
+	if q.Get("metar") != "" {
+		filters = append(filters, HasMETAR)
+	}
+	if q.Get("taf") != "" {
+		filters = append(filters, HasTAF)
+	}
+	_, index := s.stations()
+	s.writeJSON(w, r, index.Nearest(lat, lon, n, filters...), time.Time{}, 24*time.Hour)
+}
+
+// writeJSON sends the value with caching headers, answering conditional requests
+// with 304 Not Modified when the data is not newer.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v interface{}, modified time.Time, maxAge time.Duration) {
+	h := w.Header()
+	h.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	if !modified.IsZero() {
+		modified = modified.UTC().Truncate(time.Second)
+		h.Set("Last-Modified", modified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	h.Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseFloats(s string, n int) ([]float64, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+	values := make([]float64, n)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+// inBox keeps the stations inside the box, which crosses the antimeridian when minLon > maxLon.
+func inBox(minLat, minLon, maxLat, maxLon float64) StationFilter {
+	return func(s *Station) bool {
+		lat, lon := float64(s.Latitude), float64(s.Longitude)
+		if lat < minLat || lat > maxLat {
+			return false
+		}
+		if minLon <= maxLon {
+			return lon >= minLon && lon <= maxLon
+		}
+		return lon >= minLon || lon <= maxLon
+	}
+}
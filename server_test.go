@@ -0,0 +1,103 @@
+package addstogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServer(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	Convey("Server over a store", t, func() {
+		store := NewStore()
+		store.AddMETAR(METAR{StationID: "KSEA", ObservationTime: t0.Add(-time.Hour), RawText: "KSEA 070753Z"})
+		store.AddMETAR(METAR{StationID: "KSEA", ObservationTime: t0, RawText: "KSEA 070853Z"})
+		store.AddTAF(TAF{StationID: "KSEA", IssueTime: t0.Add(-3 * time.Hour), RawText: "TAF KSEA 070520Z"})
+		si, _ := UnmarshalStationsInfo([]byte(stationsFixture))
+		s := NewServer(StoreSource(store), NewCatalog(si.Data.Station))
+		s.now = func() time.Time { return t0.Add(10 * time.Minute) }
+		get := func(url string, header ...string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			for i := 0; i+1 < len(header); i += 2 {
+				req.Header.Set(header[i], header[i+1])
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			return w
+		}
+
+		Convey("should serve the latest METAR with caching headers", func() {
+			w := get("/metar/ksea")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=60")
+			So(w.Header().Get("Last-Modified"), ShouldEqual, "Fri, 07 Jun 2019 09:00:00 GMT")
+			var r METARresponse
+			So(json.Unmarshal(w.Body.Bytes(), &r), ShouldBeNil)
+			So(len(r.Data.METAR), ShouldEqual, 1)
+			So(r.Data.METAR[0].RawText, ShouldEqual, "KSEA 070853Z")
+		})
+		Convey("should serve the history of the last hours", func() {
+			var r METARresponse
+			json.Unmarshal(get("/metar/KSEA?hours=2").Body.Bytes(), &r)
+			So(r.Data.NumResults, ShouldEqual, 2)
+			So(get("/metar/KSEA?hours=x").Code, ShouldEqual, http.StatusBadRequest)
+		})
+		Convey("should answer conditional requests", func() {
+			So(get("/metar/KSEA", "If-Modified-Since", "Fri, 07 Jun 2019 09:00:00 GMT").Code, ShouldEqual, http.StatusNotModified)
+			So(get("/metar/KSEA", "If-Modified-Since", "Fri, 07 Jun 2019 08:00:00 GMT").Code, ShouldEqual, http.StatusOK)
+		})
+		Convey("should serve the latest TAF", func() {
+			var r TAFresponse
+			w := get("/taf/KSEA")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			json.Unmarshal(w.Body.Bytes(), &r)
+			So(r.Data.TAF[0].RawText, ShouldEqual, "TAF KSEA 070520Z")
+		})
+		Convey("should report unknown stations", func() {
+			So(get("/metar/ZZZZ").Code, ShouldEqual, http.StatusNotFound)
+			So(get("/taf/").Code, ShouldEqual, http.StatusBadRequest)
+		})
+		Convey("should filter stations by bounding box", func() {
+			var r StationsInfoResponse
+			w := get("/stations?bbox=35,-125,50,-100")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			json.Unmarshal(w.Body.Bytes(), &r)
+			So(stationIDs(r.Data.Station), ShouldResemble, []string{"KDEN", "KSEA"})
+			So(get("/stations?bbox=1,2").Code, ShouldEqual, http.StatusBadRequest)
+		})
+		Convey("should find the nearest stations", func() {
+			var nearest []StationDistance
+			w := get("/nearest?lat=47.6&lon=-122.3&n=1")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			json.Unmarshal(w.Body.Bytes(), &nearest)
+			So(len(nearest), ShouldEqual, 1)
+			So(nearest[0].StationID, ShouldEqual, "KSEA")
+			So(get("/nearest?lat=47.6").Code, ShouldEqual, http.StatusBadRequest)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < 100; i++ {
+					get("/stations")
+					get("/nearest?lat=47.6&lon=-122.3&n=1")
+				}
+			}()
+			s.SetCatalog(NewCatalog([]Station{{StationID: "KBFI", Latitude: 47.53, Longitude: -122.3}}))
+			<-done
+			json.Unmarshal(get("/nearest?lat=47.6&lon=-122.3&n=1").Body.Bytes(), &nearest)
+			So(nearest[0].StationID, ShouldEqual, "KBFI")
+			var si StationsInfoResponse
+			json.Unmarshal(get("/stations").Body.Bytes(), &si)
+			So(len(si.Data.Station), ShouldEqual, 1)
+		})
+		Convey("should reject other methods", func() {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/metar/KSEA", nil))
+			So(w.Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+}
@@ -0,0 +1,164 @@
+package addstogo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/urkk/addstogo/geodesy"
+)
+
+// EarthRadiusNM is the mean radius of the Earth in nautical miles.
+const EarthRadiusNM = geodesy.EarthRadiusNM
+
+// StationDistance is a station found by a spatial query.
+type StationDistance struct {
+	Station
+	DistanceNM float64
+}
+
+// StationIndex is a k-d tree of stations over their positions on the unit sphere,
+// which keeps queries correct across the antimeridian and near the poles.
+type StationIndex struct {
+	nodes []kdNode
+}
+
+type kdNode struct {
+	point   geodesy.Vector
+	station Station
+}
+
+// NewStationIndex builds the index, e.g. from StationsInfoResponse.Data.Station.
+func NewStationIndex(stations []Station) *StationIndex {
+	x := &StationIndex{nodes: make([]kdNode, len(stations))}
+	for i, s := range stations {
+		x.nodes[i] = kdNode{point: s.Position().Vector(), station: s}
+	}
+	x.build(0, len(x.nodes), 0)
+	return x
+}
+
+// Len returns the number of indexed stations.
+func (x *StationIndex) Len() int {
+	return len(x.nodes)
+}
+
+// build arranges nodes[lo:hi] so that the median on the axis is in the middle.
+func (x *StationIndex) build(lo, hi, axis int) {
+	if hi-lo <= 1 {
+		return
+	}
+	part := x.nodes[lo:hi]
+	sort.Slice(part, func(i, j int) bool { return part[i].point[axis] < part[j].point[axis] })
+	mid := lo + (hi-lo)/2
+	x.build(lo, mid, (axis+1)%3)
+	x.build(mid+1, hi, (axis+1)%3)
+}
+
+func chordSquared(a, b geodesy.Vector) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+// chordToNM converts a squared chord length on the unit sphere to a great-circle distance.
+func chordToNM(c2 float64) float64 {
+	return 2 * math.Asin(math.Min(1, math.Sqrt(c2)/2)) * EarthRadiusNM
+}
+
+// nmToChord converts a great-circle distance to a squared chord length on the unit sphere.
+func nmToChord(nm float64) float64 {
+	if nm >= math.Pi*EarthRadiusNM {
+		return 4
+	}
+	c := 2 * math.Sin(nm/EarthRadiusNM/2)
+	return c * c
+}
+
+// candidates is a max-heap of the best stations found so far.
+type candidates []kdCandidate
+
+type kdCandidate struct {
+	node *kdNode
+	c2   float64
+}
+
+func (h candidates) Len() int            { return len(h) }
+func (h candidates) Less(i, j int) bool  { return h[i].c2 > h[j].c2 }
+func (h candidates) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidates) Push(v interface{}) { *h = append(*h, v.(kdCandidate)) }
+func (h *candidates) Pop() interface{} {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// Nearest returns up to n stations matching every filter, closest first.
+func (x *StationIndex) Nearest(lat, lon float64, n int, filters ...StationFilter) []StationDistance {
+	if n <= 0 {
+		return nil
+	}
+	target := geodesy.Point{Latitude: lat, Longitude: lon}.Vector()
+	h := make(candidates, 0, n)
+	var search func(lo, hi, axis int)
+	search = func(lo, hi, axis int) {
+		if lo >= hi {
+			return
+		}
+		mid := lo + (hi-lo)/2
+		node := &x.nodes[mid]
+		if matchAll(&node.station, filters) {
+			c2 := chordSquared(target, node.point)
+			if len(h) < n {
+				heap.Push(&h, kdCandidate{node, c2})
+			} else if c2 < h[0].c2 {
+				h[0] = kdCandidate{node, c2}
+				heap.Fix(&h, 0)
+			}
+		}
+		diff := target[axis] - node.point[axis]
+		near, far := [2]int{lo, mid}, [2]int{mid + 1, hi}
+		if diff > 0 {
+			near, far = far, near
+		}
+		search(near[0], near[1], (axis+1)%3)
+		if len(h) < n || diff*diff < h[0].c2 {
+			search(far[0], far[1], (axis+1)%3)
+		}
+	}
+	search(0, len(x.nodes), 0)
+	result := make([]StationDistance, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		c := heap.Pop(&h).(kdCandidate)
+		result[i] = StationDistance{Station: c.node.station, DistanceNM: chordToNM(c.c2)}
+	}
+	return result
+}
+
+// WithinRadius returns the stations matching every filter no farther than radiusNM, closest first.
+func (x *StationIndex) WithinRadius(lat, lon, radiusNM float64, filters ...StationFilter) []StationDistance {
+	target := geodesy.Point{Latitude: lat, Longitude: lon}.Vector()
+	limit := nmToChord(radiusNM)
+	var result []StationDistance
+	var search func(lo, hi, axis int)
+	search = func(lo, hi, axis int) {
+		if lo >= hi {
+			return
+		}
+		mid := lo + (hi-lo)/2
+		node := &x.nodes[mid]
+		if c2 := chordSquared(target, node.point); c2 <= limit && matchAll(&node.station, filters) {
+			result = append(result, StationDistance{Station: node.station, DistanceNM: chordToNM(c2)})
+		}
+		diff := target[axis] - node.point[axis]
+		if diff <= 0 || diff*diff <= limit {
+			search(lo, mid, (axis+1)%3)
+		}
+		if diff >= 0 || diff*diff <= limit {
+			search(mid+1, hi, (axis+1)%3)
+		}
+	}
+	search(0, len(x.nodes), 0)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].DistanceNM < result[j].DistanceNM })
+	return result
+}
@@ -0,0 +1,94 @@
+package addstogo
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/urkk/addstogo/geodesy"
+)
+
+func randomStations(n int) []Station {
+	r := rand.New(rand.NewSource(1))
+	stations := make([]Station, n)
+	for i := range stations {
+		stations[i] = Station{
+			StationID: fmt.Sprintf("S%04d", i),
+			Latitude:  float32(math.Asin(2*r.Float64()-1) * 180 / math.Pi),
+			Longitude: float32(r.Float64()*360 - 180),
+		}
+		stations[i].SiteType.METAR = true
+		stations[i].SiteType.TAF = i%3 == 0
+	}
+	return stations
+}
+
+// bruteForce returns the distances from the point to all matching stations, closest first.
+func bruteForce(stations []Station, lat, lon float64, filters ...StationFilter) []StationDistance {
+	target := geodesy.Point{Latitude: lat, Longitude: lon}.Vector()
+	var result []StationDistance
+	for i := range stations {
+		if matchAll(&stations[i], filters) {
+			c2 := chordSquared(target, stations[i].Position().Vector())
+			result = append(result, StationDistance{Station: stations[i], DistanceNM: chordToNM(c2)})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].DistanceNM < result[j].DistanceNM })
+	return result
+}
+
+func TestStationIndex(t *testing.T) {
+	Convey("Station index built from station XML", t, func() {
+		si, err := UnmarshalStationsInfo([]byte(stationsFixture))
+		So(err, ShouldBeNil)
+		x := NewStationIndex(si.Data.Station)
+		So(x.Len(), ShouldEqual, 4)
+
+		Convey("should return the nearest stations closest first", func() {
+			nearest := x.Nearest(47.6, -122.3, 2)
+			So(stationIDs([]Station{nearest[0].Station, nearest[1].Station}), ShouldResemble, []string{"KSEA", "KDEN"})
+			So(nearest[0].DistanceNM, ShouldAlmostEqual, 9, 0.1)
+			So(nearest[1].DistanceNM, ShouldAlmostEqual, 893, 1)
+		})
+		Convey("should apply the filters", func() {
+			nearest := x.Nearest(39.85, -104.65, 1, HasTAF)
+			So(nearest[0].StationID, ShouldEqual, "KABR")
+		})
+		Convey("should return all stations within the radius", func() {
+			within := x.WithinRadius(39.85, -104.65, 1000)
+			So(len(within), ShouldEqual, 3)
+			So(within[0].StationID, ShouldEqual, "KDEN")
+			So(within[0].DistanceNM, ShouldAlmostEqual, 0, 0.01)
+			So(x.WithinRadius(0, 0, 100), ShouldBeEmpty)
+		})
+	})
+	Convey("Station index should agree with a linear scan", t, func() {
+		stations := randomStations(2000)
+		x := NewStationIndex(stations)
+		points := [][2]float64{{0, 0}, {89.9, 10}, {-89.9, -170}, {10, 179.9}, {10, -179.9}, {47.45, -122.32}}
+		for _, p := range points {
+			expected := bruteForce(stations, p[0], p[1])
+			So(x.Nearest(p[0], p[1], 10), ShouldResemble, expected[:10])
+			expectedTAF := bruteForce(stations, p[0], p[1], HasTAF)
+			So(x.Nearest(p[0], p[1], 5, HasTAF), ShouldResemble, expectedTAF[:5])
+			var within []StationDistance
+			for _, e := range expected {
+				if e.DistanceNM <= 600 {
+					within = append(within, e)
+				}
+			}
+			So(x.WithinRadius(p[0], p[1], 600), ShouldResemble, within)
+		}
+	})
+}
+
+func BenchmarkStationIndexNearest(b *testing.B) {
+	x := NewStationIndex(randomStations(10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Nearest(47.45, -122.32, 5, HasMETAR)
+	}
+}
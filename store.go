@@ -0,0 +1,259 @@
+package addstogo
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoreResult tells what the store did with a report.
+type StoreResult int
+
+const (
+	// Ignored reports duplicate one already stored.
+	Ignored StoreResult = iota
+	// Added reports are new.
+	Added
+	// Replaced reports are corrections of one already stored.
+	Replaced
+)
+
+// Store keeps METARs and TAFs in memory, indexed by station and time.
+// It is safe for concurrent use.
+type Store struct {
+	mu     sync.RWMutex
+	metars map[string][]METAR // by station, ordered by observation time
+	tafs   map[string][]TAF   // by station, ordered by issue time
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{
+		metars: make(map[string][]METAR),
+		tafs:   make(map[string][]TAF),
+	}
+}
+
+// AddMETAR stores the report unless the same station and observation time is already known.
+// A corrected report replaces the stored one.
+func (s *Store) AddMETAR(m METAR) StoreResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.metars[m.StationID]
+	i := sort.Search(len(list), func(i int) bool { return !list[i].ObservationTime.Before(m.ObservationTime) })
+	if i < len(list) && list[i].ObservationTime.Equal(m.ObservationTime) {
+		if m.QualityControlFlags.Corrected && list[i].RawText != m.RawText {
+			list[i] = m
+			return Replaced
+		}
+		return Ignored
+	}
+	list = append(list, METAR{})
+	copy(list[i+1:], list[i:])
+	list[i] = m
+	s.metars[m.StationID] = list
+	return Added
+}
+
+// isCorrectedTAF reports whether the forecast is marked as corrected or amended.
+func isCorrectedTAF(t TAF) bool {
+	for _, word := range strings.Fields(t.RawText) {
+		switch word {
+		case "COR", "AMD":
+			return true
+		}
+	}
+	return false
+}
+
+// AddTAF stores the forecast unless the same station and issue time is already known.
+// A corrected or amended forecast replaces the stored one.
+func (s *Store) AddTAF(t TAF) StoreResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.tafs[t.StationID]
+	i := sort.Search(len(list), func(i int) bool { return !list[i].IssueTime.Before(t.IssueTime) })
+	if i < len(list) && list[i].IssueTime.Equal(t.IssueTime) {
+		if isCorrectedTAF(t) && list[i].RawText != t.RawText {
+			list[i] = t
+			return Replaced
+		}
+		return Ignored
+	}
+	list = append(list, TAF{})
+	copy(list[i+1:], list[i:])
+	list[i] = t
+	s.tafs[t.StationID] = list
+	return Added
+}
+
+// AddMETARs stores every report of the response and returns how many were added or replaced.
+func (s *Store) AddMETARs(r *METARresponse) (added, replaced int) {
+	for _, m := range r.Data.METAR {
+		switch s.AddMETAR(m) {
+		case Added:
+			added++
+		case Replaced:
+			replaced++
+		}
+	}
+	return
+}
+
+// AddTAFs stores every forecast of the response and returns how many were added or replaced.
+func (s *Store) AddTAFs(r *TAFresponse) (added, replaced int) {
+	for _, t := range r.Data.TAF {
+		switch s.AddTAF(t) {
+		case Added:
+			added++
+		case Replaced:
+			replaced++
+		}
+	}
+	return
+}
+
+// Stations returns the identifiers of all stations with stored reports, sorted.
+func (s *Store) Stations() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := make(map[string]bool, len(s.metars))
+	var ids []string
+	for id := range s.metars {
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	for id := range s.tafs {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LatestMETAR returns the most recent observation of the station.
+func (s *Store) LatestMETAR(station string) (METAR, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.metars[station]
+	if len(list) == 0 {
+		return METAR{}, false
+	}
+	return list[len(list)-1], true
+}
+
+// LatestMETARs returns the most recent observation of every station, ordered by station.
+func (s *Store) LatestMETARs() []METAR {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]METAR, 0, len(s.metars))
+	for _, list := range s.metars {
+		result = append(result, list[len(list)-1])
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StationID < result[j].StationID })
+	return result
+}
+
+// METARs returns the observations of the station made in [from, to), oldest first.
+func (s *Store) METARs(station string, from, to time.Time) []METAR {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.metars[station]
+	i := sort.Search(len(list), func(i int) bool { return !list[i].ObservationTime.Before(from) })
+	j := sort.Search(len(list), func(i int) bool { return !list[i].ObservationTime.Before(to) })
+	if i >= j {
+		return nil
+	}
+	return append([]METAR(nil), list[i:j]...)
+}
+
+// METARAt returns the observation of the station in effect at t, that is the last one made no later than t.
+func (s *Store) METARAt(station string, t time.Time) (METAR, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.metars[station]
+	i := sort.Search(len(list), func(i int) bool { return list[i].ObservationTime.After(t) })
+	if i == 0 {
+		return METAR{}, false
+	}
+	return list[i-1], true
+}
+
+// LatestTAF returns the most recently issued forecast of the station.
+func (s *Store) LatestTAF(station string) (TAF, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.tafs[station]
+	if len(list) == 0 {
+		return TAF{}, false
+	}
+	return list[len(list)-1], true
+}
+
+// TAFs returns the forecasts of the station issued in [from, to), oldest first.
+func (s *Store) TAFs(station string, from, to time.Time) []TAF {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.tafs[station]
+	i := sort.Search(len(list), func(i int) bool { return !list[i].IssueTime.Before(from) })
+	j := sort.Search(len(list), func(i int) bool { return !list[i].IssueTime.Before(to) })
+	if i >= j {
+		return nil
+	}
+	return append([]TAF(nil), list[i:j]...)
+}
+
+// TAFAt returns the most recently issued forecast of the station that was issued
+// no later than t and is valid at t.
+func (s *Store) TAFAt(station string, t time.Time) (TAF, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := s.tafs[station]
+	for i := sort.Search(len(list), func(i int) bool { return list[i].IssueTime.After(t) }) - 1; i >= 0; i-- {
+		if !t.Before(list[i].ValidTimeFrom) && t.Before(list[i].ValidTimeTo) {
+			return list[i], true
+		}
+	}
+	return TAF{}, false
+}
+
+// Prune drops the observations made and the forecasts expired before the time.
+func (s *Store) Prune(before time.Time) {
+	s.PruneMETARs(before)
+	s.PruneTAFs(before)
+}
+
+// PruneMETARs drops the observations made before the time.
+func (s *Store) PruneMETARs(before time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for station, list := range s.metars {
+		i := sort.Search(len(list), func(i int) bool { return !list[i].ObservationTime.Before(before) })
+		if i == len(list) {
+			delete(s.metars, station)
+		} else if i > 0 {
+			s.metars[station] = append([]METAR(nil), list[i:]...)
+		}
+	}
+}
+
+// PruneTAFs drops the forecasts expired before the time.
+func (s *Store) PruneTAFs(before time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for station, list := range s.tafs {
+		kept := list[:0:0]
+		for _, t := range list {
+			if t.ValidTimeTo.After(before) {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.tafs, station)
+		} else {
+			s.tafs[station] = kept
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package addstogo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStore(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	Convey("Store with METARs", t, func() {
+		s := NewStore()
+		r := newMETARresponse([]METAR{
+			{StationID: "URSS", ObservationTime: t0.Add(time.Hour), RawText: "URSS 071000Z"},
+			{StationID: "URSS", ObservationTime: t0, RawText: "URSS 070900Z"},
+			{StationID: "URSS", ObservationTime: t0.Add(30 * time.Minute), RawText: "URSS 070930Z", MetarType: "SPECI"},
+			{StationID: "ULLI", ObservationTime: t0, RawText: "ULLI 070900Z"},
+		})
+		added, replaced := s.AddMETARs(r)
+		So(added, ShouldEqual, 4)
+		So(replaced, ShouldEqual, 0)
+
+		Convey("should deduplicate by station and observation time", func() {
+			added, replaced := s.AddMETARs(r)
+			So(added, ShouldEqual, 0)
+			So(replaced, ShouldEqual, 0)
+			So(s.METARs("URSS", t0, t0.Add(2*time.Hour)), ShouldHaveLength, 3)
+		})
+		Convey("should replace corrected reports", func() {
+			corrected := METAR{StationID: "URSS", ObservationTime: t0, RawText: "URSS 070900Z COR"}
+			corrected.QualityControlFlags.Corrected = true
+			So(s.AddMETAR(corrected), ShouldEqual, Replaced)
+			So(s.AddMETAR(corrected), ShouldEqual, Ignored)
+			So(s.METARs("URSS", t0, t0.Add(time.Minute))[0].RawText, ShouldEqual, "URSS 070900Z COR")
+		})
+		Convey("should answer latest per station", func() {
+			m, ok := s.LatestMETAR("URSS")
+			So(ok, ShouldBeTrue)
+			So(m.RawText, ShouldEqual, "URSS 071000Z")
+			_, ok = s.LatestMETAR("UUEE")
+			So(ok, ShouldBeFalse)
+			latest := s.LatestMETARs()
+			So(len(latest), ShouldEqual, 2)
+			So(latest[0].StationID, ShouldEqual, "ULLI")
+			So(s.Stations(), ShouldResemble, []string{"ULLI", "URSS"})
+		})
+		Convey("should answer time windows", func() {
+			window := s.METARs("URSS", t0.Add(time.Minute), t0.Add(time.Hour))
+			So(len(window), ShouldEqual, 1)
+			So(window[0].MetarType, ShouldEqual, "SPECI")
+			So(s.METARs("URSS", t0.Add(2*time.Hour), t0.Add(3*time.Hour)), ShouldBeEmpty)
+		})
+		Convey("should answer the report in effect at a time", func() {
+			m, ok := s.METARAt("URSS", t0.Add(45*time.Minute))
+			So(ok, ShouldBeTrue)
+			So(m.RawText, ShouldEqual, "URSS 070930Z")
+			_, ok = s.METARAt("URSS", t0.Add(-time.Minute))
+			So(ok, ShouldBeFalse)
+		})
+		Convey("should prune old reports", func() {
+			s.Prune(t0.Add(time.Minute))
+			So(s.Stations(), ShouldResemble, []string{"URSS"})
+			So(s.METARs("URSS", t0, t0.Add(2*time.Hour)), ShouldHaveLength, 2)
+		})
+	})
+	Convey("Store with TAFs", t, func() {
+		s := NewStore()
+		s.AddTAFs(newTAFresponse([]TAF{
+			{StationID: "URSS", IssueTime: t0.Add(-5 * time.Hour), ValidTimeFrom: t0.Add(-3 * time.Hour), ValidTimeTo: t0.Add(21 * time.Hour), RawText: "TAF URSS 070400Z"},
+			{StationID: "URSS", IssueTime: t0.Add(-time.Hour), ValidTimeFrom: t0.Add(3 * time.Hour), ValidTimeTo: t0.Add(27 * time.Hour), RawText: "TAF URSS 070800Z"},
+		}))
+		Convey("should answer the forecast valid at a time", func() {
+			taf, ok := s.TAFAt("URSS", t0)
+			So(ok, ShouldBeTrue)
+			So(taf.RawText, ShouldEqual, "TAF URSS 070400Z")
+			taf, _ = s.TAFAt("URSS", t0.Add(4*time.Hour))
+			So(taf.RawText, ShouldEqual, "TAF URSS 070800Z")
+			_, ok = s.TAFAt("URSS", t0.Add(30*time.Hour))
+			So(ok, ShouldBeFalse)
+		})
+		Convey("should replace amended forecasts", func() {
+			So(s.AddTAF(TAF{StationID: "URSS", IssueTime: t0.Add(-time.Hour), RawText: "TAF AMD URSS 070800Z"}), ShouldEqual, Replaced)
+			So(s.AddTAF(TAF{StationID: "URSS", IssueTime: t0.Add(-time.Hour), RawText: "TAF URSS 070800Z"}), ShouldEqual, Ignored)
+			taf, _ := s.LatestTAF("URSS")
+			So(taf.RawText, ShouldEqual, "TAF AMD URSS 070800Z")
+			So(s.TAFs("URSS", t0.Add(-6*time.Hour), t0), ShouldHaveLength, 2)
+		})
+	})
+	Convey("Store should be safe for concurrent use", t, func() {
+		s := NewStore()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					s.AddMETAR(METAR{StationID: fmt.Sprintf("S%d", i%4), ObservationTime: t0.Add(time.Duration(j) * time.Minute)})
+					s.LatestMETARs()
+				}
+			}(i)
+		}
+		wg.Wait()
+		So(s.Stations(), ShouldHaveLength, 4)
+		So(s.METARs("S0", t0, t0.Add(time.Hour*2)), ShouldHaveLength, 100)
+	})
+}
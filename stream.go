@@ -0,0 +1,233 @@
+package addstogo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamEvent is a new or changed report sent by Broadcaster.
+type StreamEvent struct {
+	ID uint64 `json:"id"`
+	// Type is "metar" or "taf".
+	Type  string `json:"type"`
+	METAR *METAR `json:"metar,omitempty"`
+	TAF   *TAF   `json:"taf,omitempty"`
+}
+
+func (e *StreamEvent) station() *Station {
+	if e.METAR != nil {
+		return &Station{StationID: e.METAR.StationID, Latitude: e.METAR.Latitude, Longitude: e.METAR.Longitude}
+	}
+	return &Station{StationID: e.TAF.StationID, Latitude: e.TAF.Latitude, Longitude: e.TAF.Longitude}
+}
+
+// Broadcaster is an http.Handler streaming reports as Server-Sent Events.
+// Clients may narrow the stream with the query parameters
+// stations=KSEA,KDEN, bbox=minLat,minLon,maxLat,maxLon, category=IFR,LIFR (METARs only) and type=metar or taf,
+// and resume after a reconnect from the recent history with the Last-Event-ID header.
+type Broadcaster struct {
+	// KeepAlive is the interval of the comments keeping idle connections open.
+	KeepAlive time.Duration
+
+	mu          sync.Mutex
+	history     []StreamEvent
+	size        int
+	nextID      uint64
+	subscribers map[chan StreamEvent]struct{}
+}
+
+// NewBroadcaster returns a broadcaster keeping the last history events for resuming clients.
+func NewBroadcaster(history int) *Broadcaster {
+	return &Broadcaster{
+		KeepAlive:   30 * time.Second,
+		size:        history,
+		nextID:      1,
+		subscribers: make(map[chan StreamEvent]struct{}),
+	}
+}
+
+// PublishMETAR sends the observation to every subscriber.
+func (b *Broadcaster) PublishMETAR(m METAR) {
+	b.publish(StreamEvent{Type: "metar", METAR: &m})
+}
+
+// PublishTAF sends the forecast to every subscriber.
+func (b *Broadcaster) PublishTAF(t TAF) {
+	b.publish(StreamEvent{Type: "taf", TAF: &t})
+}
+
+func (b *Broadcaster) publish(e StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e.ID = b.nextID
+	b.nextID++
+	if b.size > 0 {
+		if len(b.history) == b.size {
+			copy(b.history, b.history[1:])
+			b.history = b.history[:b.size-1]
+		}
+		b.history = append(b.history, e)
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			// a subscriber too slow to keep up is dropped and resumes from the history
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the history after the event and a channel of the events to come.
+func (b *Broadcaster) subscribe(after uint64) ([]StreamEvent, chan StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var replay []StreamEvent
+	for _, e := range b.history {
+		if e.ID > after {
+			replay = append(replay, e)
+		}
+	}
+	ch := make(chan StreamEvent, 64)
+	b.subscribers[ch] = struct{}{}
+	return replay, ch
+}
+
+func (b *Broadcaster) unsubscribe(ch chan StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Feed polls the sources every interval until the context is done, publishing the reports
+// not seen before and the corrected ones. Either source may be nil. A non-positive interval is an error.
+func (b *Broadcaster) Feed(ctx context.Context, metars METARSource, tafs TAFSource, interval time.Duration, onError func(error)) error {
+	w := NewWatcher(metars, interval)
+	w.TAFSource = tafs
+	w.OnError = onError
+	return w.RunReports(ctx, func(changes []METARChange, tafs []TAF) {
+		for _, c := range changes {
+			b.PublishMETAR(c.METAR)
+		}
+		for _, t := range tafs {
+			b.PublishTAF(t)
+		}
+	})
+}
+
+// streamFilter tells whether a client asked for the event.
+type streamFilter func(e *StreamEvent) bool
+
+func parseStreamFilters(r *http.Request) ([]streamFilter, error) {
+	q := r.URL.Query()
+	var filters []streamFilter
+	if v := q.Get("type"); v != "" {
+		kind := strings.ToLower(v)
+		filters = append(filters, func(e *StreamEvent) bool { return e.Type == kind })
+	}
+	if v := q.Get("stations"); v != "" {
+		stations := make(map[string]bool)
+		for _, s := range strings.Split(v, ",") {
+			stations[strings.ToUpper(strings.TrimSpace(s))] = true
+		}
+		filters = append(filters, func(e *StreamEvent) bool { return stations[e.station().StationID] })
+	}
+	if v := q.Get("bbox"); v != "" {
+		box, ok := parseFloats(v, 4)
+		if !ok {
+			return nil, fmt.Errorf("bbox expected as minLat,minLon,maxLat,maxLon")
+		}
+		in := inBox(box[0], box[1], box[2], box[3])
+		filters = append(filters, func(e *StreamEvent) bool { return in(e.station()) })
+	}
+	if v := q.Get("category"); v != "" {
+		categories := make(map[string]bool)
+		for _, c := range strings.Split(v, ",") {
+			categories[strings.ToUpper(strings.TrimSpace(c))] = true
+		}
+		filters = append(filters, func(e *StreamEvent) bool { return e.METAR == nil || categories[e.METAR.FlightCategory] })
+	}
+	return filters, nil
+}
+
+// ServeHTTP streams the events matching the query until the client goes away.
+func (b *Broadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	filters, err := parseStreamFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	after, _ := strconv.ParseUint(lastID, 10, 64)
+	replay, ch := b.subscribe(after)
+	defer b.unsubscribe(ch)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	send := func(e StreamEvent) error {
+		for _, f := range filters {
+			if !f(&e) {
+				return nil
+			}
+		}
+		var data []byte
+		if e.METAR != nil {
+			data, err = json.Marshal(e.METAR)
+		} else {
+			data, err = json.Marshal(e.TAF)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		return err
+	}
+	for _, e := range replay {
+		if send(e) != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(b.KeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if send(e) != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
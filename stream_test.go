@@ -0,0 +1,138 @@
+package addstogo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// readEvent reads one event, skipping comments.
+func readEvent(r *bufio.Reader) (sseEvent, error) {
+	var e sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return e, err
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && e.event != "":
+			return e, nil
+		case strings.HasPrefix(line, "id: "):
+			e.id = line[4:]
+		case strings.HasPrefix(line, "event: "):
+			e.event = line[7:]
+		case strings.HasPrefix(line, "data: "):
+			e.data = line[6:]
+		}
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	Convey("Broadcaster with history", t, func() {
+		b := NewBroadcaster(3)
+		b.PublishMETAR(METAR{StationID: "KSEA", ObservationTime: t0, FlightCategory: "VFR", Latitude: 47.45, Longitude: -122.32})
+		b.PublishMETAR(METAR{StationID: "KDEN", ObservationTime: t0, FlightCategory: "IFR", Latitude: 39.85, Longitude: -104.65})
+		b.PublishTAF(TAF{StationID: "KSEA", IssueTime: t0, RawText: "TAF KSEA", Latitude: 47.45, Longitude: -122.32})
+		b.PublishMETAR(METAR{StationID: "KSEA", ObservationTime: t0.Add(time.Hour), FlightCategory: "IFR", Latitude: 47.45, Longitude: -122.32})
+		server := httptest.NewServer(b)
+		defer server.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		open := func(query, lastID string) (*bufio.Reader, *http.Response) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+query, nil)
+			if lastID != "" {
+				req.Header.Set("Last-Event-ID", lastID)
+			}
+			resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+			So(err, ShouldBeNil)
+			return bufio.NewReader(resp.Body), resp
+		}
+
+		Convey("should resume from the history and stream new events", func() {
+			r, resp := open("?stations=ksea", "2")
+			So(resp.Header.Get("Content-Type"), ShouldEqual, "text/event-stream")
+			e, err := readEvent(r)
+			So(err, ShouldBeNil)
+			So(e.id, ShouldEqual, "3")
+			So(e.event, ShouldEqual, "taf")
+			e, _ = readEvent(r)
+			So(e.id, ShouldEqual, "4")
+			var m METAR
+			So(json.Unmarshal([]byte(e.data), &m), ShouldBeNil)
+			So(m.ObservationTime.Equal(t0.Add(time.Hour)), ShouldBeTrue)
+
+			b.PublishMETAR(METAR{StationID: "KDEN", ObservationTime: t0.Add(time.Hour)})
+			b.PublishMETAR(METAR{StationID: "KSEA", ObservationTime: t0.Add(2 * time.Hour), RawText: "KSEA live"})
+			e, _ = readEvent(r)
+			So(e.id, ShouldEqual, "6")
+			So(e.data, ShouldContainSubstring, "KSEA live")
+		})
+		Convey("should keep only the recent history", func() {
+			r, _ := open("?type=metar", "")
+			e, _ := readEvent(r)
+			So(e.id, ShouldEqual, "2")
+		})
+		Convey("should filter by bounding box and flight category", func() {
+			r, _ := open("?bbox=35,-110,45,-100&category=IFR,LIFR", "0")
+			e, _ := readEvent(r)
+			So(e.id, ShouldEqual, "2")
+			b.PublishMETAR(METAR{StationID: "KDEN", Latitude: 39.85, Longitude: -104.65, FlightCategory: "VFR"})
+			b.PublishMETAR(METAR{StationID: "KSEA", Latitude: 47.45, Longitude: -122.32, FlightCategory: "LIFR"})
+			b.PublishMETAR(METAR{StationID: "KDEN", Latitude: 39.85, Longitude: -104.65, FlightCategory: "LIFR"})
+			e, _ = readEvent(r)
+			So(e.id, ShouldEqual, "7")
+		})
+		Convey("should reject a bad bounding box", func() {
+			resp, err := http.Get(server.URL + "?bbox=1")
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+	Convey("Broadcaster fed by polling sources", t, func() {
+		b := NewBroadcaster(10)
+		var polls int32
+		metars := func(ctx context.Context) (*METARresponse, error) {
+			n := atomic.AddInt32(&polls, 1)
+			return newMETARresponse([]METAR{{StationID: "URSS", ObservationTime: t0.Add(time.Duration(n/2) * time.Hour)}}), nil
+		}
+		tafs := func(ctx context.Context) (*TAFresponse, error) {
+			return newTAFresponse([]TAF{
+				{StationID: "URSS", IssueTime: t0.Add(-30 * time.Hour), ValidTimeTo: t0.Add(-6 * time.Hour)},
+				{StationID: "URSS", IssueTime: t0, ValidTimeTo: t0.Add(24 * time.Hour)},
+			}), nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			for atomic.LoadInt32(&polls) < 4 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+		So(b.Feed(ctx, metars, tafs, time.Millisecond, nil), ShouldResemble, context.Canceled)
+		replay, _ := b.subscribe(0)
+		So(len(replay), ShouldBeGreaterThanOrEqualTo, 3)
+		So(replay[0].Type, ShouldEqual, "metar")
+		So(replay[1].Type, ShouldEqual, "taf")
+		So(replay[1].TAF.IssueTime, ShouldResemble, t0)
+		So(replay[2].METAR.ObservationTime, ShouldResemble, t0.Add(time.Hour))
+		for _, e := range replay[2:] {
+			So(e.Type, ShouldEqual, "metar")
+		}
+		So(b.Feed(context.Background(), metars, tafs, 0, nil), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,130 @@
+package addstogo
+
+import (
+	"math"
+	"time"
+)
+
+// Zenith angles in degrees of the events. Sunrise and sunset account for refraction and the solar disc.
+const (
+	sunriseZenith = 90.833
+	civilZenith   = 96
+)
+
+// SunTimes are the solar events of a day at a station, in UTC. An event is zero when
+// the sun does not cross its altitude that day, as in polar day and polar night.
+type SunTimes struct {
+	CivilDawn time.Time
+	Sunrise   time.Time
+	Sunset    time.Time
+	CivilDusk time.Time
+}
+
+// sunPosition returns the solar declination in radians and the equation of time in minutes (NOAA algorithm).
+func sunPosition(t time.Time) (decl, eqTime float64) {
+	jd := float64(t.UnixNano())/86400e9 + 2440587.5
+	jc := (jd - 2451545) / 36525
+	rad := math.Pi / 180
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360) * rad
+	meanAnom := (357.52911 + jc*(35999.05029-0.0001537*jc)) * rad
+	ecc := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+	center := (math.Sin(meanAnom)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(2*meanAnom)*(0.019993-0.000101*jc) + math.Sin(3*meanAnom)*0.000289) * rad
+	omega := (125.04 - 1934.136*jc) * rad
+	appLong := meanLong + center - (0.00569+0.00478*math.Sin(omega))*rad
+	meanObliq := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliq := (meanObliq + 0.00256*math.Cos(omega)) * rad
+	decl = math.Asin(math.Sin(obliq) * math.Sin(appLong))
+	y := math.Tan(obliq / 2)
+	y *= y
+	eqTime = 4 / rad * (y*math.Sin(2*meanLong) - 2*ecc*math.Sin(meanAnom) +
+		4*ecc*y*math.Sin(meanAnom)*math.Cos(2*meanLong) -
+		0.5*y*y*math.Sin(4*meanLong) - 1.25*ecc*ecc*math.Sin(2*meanAnom))
+	return decl, eqTime
+}
+
+// SolarElevation returns the geometric elevation of the centre of the sun above the horizon in degrees.
+func SolarElevation(p Positioned, t time.Time) float64 {
+	pos := p.Position()
+	decl, eqTime := sunPosition(t)
+	t = t.UTC()
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	hourAngle := ((minutes+eqTime+4*pos.Longitude)/4 - 180) * math.Pi / 180
+	lat := pos.Latitude * math.Pi / 180
+	cosZenith := math.Sin(lat)*math.Sin(decl) + math.Cos(lat)*math.Cos(decl)*math.Cos(hourAngle)
+	return 90 - math.Acos(math.Max(-1, math.Min(1, cosZenith)))*180/math.Pi
+}
+
+// solarNoon returns the solar noon nearest to noon of the UTC day shifted by the longitude.
+func solarNoon(lon float64, day time.Time) time.Time {
+	noon := day.Add(time.Duration((720 - 4*lon) * float64(time.Minute)))
+	for i := 0; i < 2; i++ {
+		_, eqTime := sunPosition(noon)
+		noon = day.Add(time.Duration((720 - 4*lon - eqTime) * float64(time.Minute)))
+	}
+	return noon
+}
+
+// sunEvent returns the time the sun crosses the zenith angle before (sign -1) or after (sign 1) the solar noon.
+func sunEvent(lat, lon float64, day, noon time.Time, zenith float64, sign float64) time.Time {
+	rad := math.Pi / 180
+	t := noon
+	for i := 0; i < 3; i++ {
+		decl, eqTime := sunPosition(t)
+		cosHA := math.Cos(zenith*rad)/(math.Cos(lat*rad)*math.Cos(decl)) - math.Tan(lat*rad)*math.Tan(decl)
+		if cosHA < -1 || cosHA > 1 {
+			return time.Time{}
+		}
+		ha := math.Acos(cosHA) / rad
+		t = day.Add(time.Duration((720 - 4*(lon-sign*ha) - eqTime) * float64(time.Minute)))
+	}
+	return t
+}
+
+// SunTimesOn returns the solar events at the station on the day of date. The day is the UTC calendar
+// day of date, shifted by the longitude so that the events belong to the station's local solar day.
+func SunTimesOn(p Positioned, date time.Time) SunTimes {
+	pos := p.Position()
+	date = date.UTC()
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	noon := solarNoon(pos.Longitude, day)
+	return SunTimes{
+		CivilDawn: sunEvent(pos.Latitude, pos.Longitude, day, noon, civilZenith, -1),
+		Sunrise:   sunEvent(pos.Latitude, pos.Longitude, day, noon, sunriseZenith, -1),
+		Sunset:    sunEvent(pos.Latitude, pos.Longitude, day, noon, sunriseZenith, 1),
+		CivilDusk: sunEvent(pos.Latitude, pos.Longitude, day, noon, civilZenith, 1),
+	}
+}
+
+// IsNight tells whether the time falls between the end of evening civil twilight and
+// the beginning of morning civil twilight at the station.
+func IsNight(p Positioned, t time.Time) bool {
+	return SolarElevation(p, t) < 90-civilZenith
+}
+
+// NightDuring tells whether any part of the period from one time to the other is night at the station.
+func NightDuring(p Positioned, from, to time.Time) bool {
+	if IsNight(p, from) || IsNight(p, to) {
+		return true
+	}
+	// the sun is lowest at solar midnight, half a day from the solar noons
+	lon := p.Position().Longitude
+	day := time.Date(from.UTC().Year(), from.UTC().Month(), from.UTC().Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for ; !day.After(to); day = day.AddDate(0, 0, 1) {
+		midnight := solarNoon(lon, day).Add(12 * time.Hour)
+		if midnight.After(from) && midnight.Before(to) && IsNight(p, midnight) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNight tells whether the observation was made at night at the station.
+func (m METAR) IsNight() bool {
+	return IsNight(m, m.ObservationTime)
+}
+
+// IsNight tells whether any part of the forecast period is night at the station.
+func (t TAF) IsNight(f Forecast) bool {
+	return NightDuring(t, f.FcstTimeFrom, f.FcstTimeTo)
+}
@@ -0,0 +1,54 @@
+package addstogo
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSun(t *testing.T) {
+	greenwich := Station{StationID: "EGLC", Latitude: 51.4778, Longitude: -0.0015}
+	sydney := Station{StationID: "YSSY", Latitude: -33.95, Longitude: 151.18}
+	svalbard := Station{StationID: "ENSB", Latitude: 78.25, Longitude: 15.47}
+	near := func(t time.Time, hhmm string, day int) {
+		expected, _ := time.Parse("2006-01-02 15:04", hhmm)
+		So(t.Day(), ShouldEqual, day)
+		So(t.Sub(time.Date(t.Year(), t.Month(), t.Day(), expected.Hour(), expected.Minute(), 0, 0, time.UTC)), ShouldBeBetween, -2*time.Minute, 2*time.Minute)
+	}
+	Convey("Sun times should match the almanac", t, func() {
+		s := SunTimesOn(greenwich, time.Date(2020, 6, 21, 15, 0, 0, 0, time.UTC))
+		near(s.CivilDawn, "2020-06-21 02:55", 21)
+		near(s.Sunrise, "2020-06-21 03:43", 21)
+		near(s.Sunset, "2020-06-21 20:21", 21)
+		near(s.CivilDusk, "2020-06-21 21:09", 21)
+		s = SunTimesOn(greenwich, time.Date(2020, 12, 21, 0, 0, 0, 0, time.UTC))
+		near(s.Sunrise, "2020-12-21 08:04", 21)
+		near(s.Sunset, "2020-12-21 15:53", 21)
+	})
+	Convey("The day should be the local solar day of the station", t, func() {
+		s := SunTimesOn(sydney, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+		near(s.Sunrise, "2018-12-31 18:47", 31)
+		near(s.Sunset, "2019-01-01 09:10", 1)
+	})
+	Convey("Polar day and night should have no events", t, func() {
+		So(SunTimesOn(svalbard, time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC)), ShouldResemble, SunTimes{})
+		So(SunTimesOn(svalbard, time.Date(2020, 12, 21, 0, 0, 0, 0, time.UTC)), ShouldResemble, SunTimes{})
+		So(IsNight(svalbard, time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC)), ShouldBeFalse)
+		So(IsNight(svalbard, time.Date(2020, 12, 21, 12, 0, 0, 0, time.UTC)), ShouldBeTrue)
+	})
+	Convey("Night should start at the end of civil twilight", t, func() {
+		So(SolarElevation(greenwich, time.Date(2020, 6, 21, 12, 2, 0, 0, time.UTC)), ShouldAlmostEqual, 62.0, 0.1)
+		m := METAR{Latitude: greenwich.Latitude, Longitude: greenwich.Longitude, ObservationTime: time.Date(2020, 12, 21, 16, 20, 0, 0, time.UTC)}
+		So(m.IsNight(), ShouldBeFalse)
+		m.ObservationTime = time.Date(2020, 12, 21, 16, 50, 0, 0, time.UTC)
+		So(m.IsNight(), ShouldBeTrue)
+	})
+	Convey("A forecast period should be night when any part of it is", t, func() {
+		taf := TAF{Latitude: greenwich.Latitude, Longitude: greenwich.Longitude}
+		day := time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC)
+		So(taf.IsNight(Forecast{FcstTimeFrom: day.Add(6 * time.Hour), FcstTimeTo: day.Add(18 * time.Hour)}), ShouldBeFalse)
+		So(taf.IsNight(Forecast{FcstTimeFrom: day.Add(18 * time.Hour), FcstTimeTo: day.Add(30 * time.Hour)}), ShouldBeTrue)
+		So(taf.IsNight(Forecast{FcstTimeFrom: day.Add(12 * time.Hour), FcstTimeTo: day.Add(36 * time.Hour)}), ShouldBeTrue)
+	})
+}
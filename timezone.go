@@ -0,0 +1,114 @@
+package addstogo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urkk/addstogo/geodesy"
+)
+
+type zoneRef struct {
+	zone   string
+	points []geodesy.Point
+}
+
+var zonesByPrefix = parseZoneTable(zoneTable)
+
+func parseZoneTable(table string) map[string][]zoneRef {
+	zones := make(map[string][]zoneRef)
+	for _, line := range strings.Split(table, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ref := zoneRef{zone: fields[1]}
+		for i := 2; i+1 < len(fields); i += 2 {
+			lat, _ := strconv.ParseFloat(fields[i], 64)
+			lon, _ := strconv.ParseFloat(fields[i+1], 64)
+			ref.points = append(ref.points, geodesy.Point{Latitude: lat, Longitude: lon})
+		}
+		for _, prefix := range strings.Split(fields[0], ",") {
+			zones[prefix] = append(zones[prefix], ref)
+		}
+	}
+	return zones
+}
+
+// TimeZone returns the IANA time zone of a station from the prefix of its ICAO identifier.
+// In countries with several zones the zone of the reference city nearest to the position is taken,
+// so stations close to a zone boundary may be misplaced. It returns false for unknown prefixes.
+func TimeZone(stationID string, p Positioned) (string, bool) {
+	stationID = strings.ToUpper(stationID)
+	for n := len(stationID); n > 0; n-- {
+		refs, ok := zonesByPrefix[stationID[:n]]
+		if !ok {
+			continue
+		}
+		if len(refs) == 1 || p == nil {
+			return refs[0].zone, true
+		}
+		pos := p.Position()
+		best, bestDistance := refs[0].zone, -1.0
+		for _, ref := range refs {
+			for _, point := range ref.points {
+				if d := geodesy.DistanceKm(pos, point); bestDistance < 0 || d < bestDistance {
+					best, bestDistance = ref.zone, d
+				}
+			}
+		}
+		return best, true
+	}
+	return "", false
+}
+
+var locations = struct {
+	sync.Mutex
+	m map[string]*time.Location
+}{m: make(map[string]*time.Location)}
+
+// StationLocation returns the time zone of a station with its daylight saving time rules,
+// loaded from the zoneinfo database of the system.
+func StationLocation(stationID string, p Positioned) (*time.Location, error) {
+	zone, ok := TimeZone(stationID, p)
+	if !ok {
+		return nil, fmt.Errorf("addstogo: unknown time zone of station %q", stationID)
+	}
+	locations.Lock()
+	defer locations.Unlock()
+	if loc, ok := locations.m[zone]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("addstogo: %v", err)
+	}
+	locations.m[zone] = loc
+	return loc, nil
+}
+
+// Location returns the local time zone of the station.
+func (s Station) Location() (*time.Location, error) {
+	return StationLocation(s.StationID, s)
+}
+
+// Location returns the local time zone of the station of the observation.
+func (m METAR) Location() (*time.Location, error) {
+	return StationLocation(m.StationID, m)
+}
+
+// Location returns the local time zone of the station of the forecast, in which its periods may be shown with time.In.
+func (t TAF) Location() (*time.Location, error) {
+	return StationLocation(t.StationID, t)
+}
+
+// LocalObservationTime returns the observation time in the local time of the station.
+func (m METAR) LocalObservationTime() (time.Time, error) {
+	loc, err := m.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return m.ObservationTime.In(loc), nil
+}
@@ -0,0 +1,87 @@
+package addstogo
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/urkk/addstogo/geodesy"
+)
+
+func TestTimeZone(t *testing.T) {
+	Convey("Stations should get the time zone of their prefix", t, func() {
+		for id, zone := range map[string]string{
+			"EGLL": "Europe/London", "LFPG": "Europe/Paris", "UMKK": "Europe/Kaliningrad",
+			"UMMS": "Europe/Minsk", "NZCI": "Pacific/Chatham", "rjtt": "Asia/Tokyo",
+		} {
+			z, ok := TimeZone(id, nil)
+			So(ok, ShouldBeTrue)
+			So(z, ShouldEqual, zone)
+		}
+		_, ok := TimeZone("XXXX", nil)
+		So(ok, ShouldBeFalse)
+	})
+	Convey("Countries with several zones should be resolved by position", t, func() {
+		for _, s := range []struct {
+			id       string
+			lat, lon float32
+			zone     string
+		}{
+			{"KJFK", 40.64, -73.78, "America/New_York"},
+			{"KORD", 41.98, -87.90, "America/Chicago"},
+			{"KDEN", 39.86, -104.67, "America/Denver"},
+			{"KPHX", 33.43, -112.01, "America/Phoenix"},
+			{"KSEA", 47.45, -122.31, "America/Los_Angeles"},
+			{"PANC", 61.17, -150.00, "America/Anchorage"},
+			{"UHPP", 53.17, 158.45, "Asia/Kamchatka"},
+			{"UNNT", 55.01, 82.65, "Asia/Novosibirsk"},
+			{"UNOO", 54.97, 73.31, "Asia/Omsk"},
+			{"USSS", 56.74, 60.80, "Asia/Yekaterinburg"},
+			{"UWWW", 53.50, 50.16, "Europe/Samara"},
+			{"ULLI", 59.80, 30.26, "Europe/Moscow"},
+			{"YPPH", -31.94, 115.97, "Australia/Perth"},
+			{"SBEG", -3.04, -60.05, "America/Manaus"},
+		} {
+			z, _ := TimeZone(s.id, Station{Latitude: s.lat, Longitude: s.lon})
+			So(z, ShouldEqual, s.zone)
+		}
+	})
+	Convey("No reference point should belong to two zones of a prefix", t, func() {
+		var shared []string
+		for prefix, refs := range zonesByPrefix {
+			zoneOf := make(map[geodesy.Point]string)
+			for _, ref := range refs {
+				for _, p := range ref.points {
+					if zone, ok := zoneOf[p]; ok && zone != ref.zone {
+						shared = append(shared, fmt.Sprintf("%s %v in %s and %s", prefix, p, zone, ref.zone))
+					}
+					zoneOf[p] = ref.zone
+				}
+			}
+		}
+		So(shared, ShouldBeEmpty)
+	})
+	Convey("Every zone of the table should load", t, func() {
+		for _, refs := range zonesByPrefix {
+			for _, ref := range refs {
+				_, err := time.LoadLocation(ref.zone)
+				So(err, ShouldBeNil)
+			}
+		}
+	})
+	Convey("Reports should be rendered in local time with daylight saving time", t, func() {
+		m := METAR{StationID: "EGLL", ObservationTime: time.Date(2019, 7, 1, 12, 20, 0, 0, time.UTC)}
+		local, err := m.LocalObservationTime()
+		So(err, ShouldBeNil)
+		So(local.Format("15:04 MST"), ShouldEqual, "13:20 BST")
+		m.ObservationTime = time.Date(2019, 1, 1, 12, 20, 0, 0, time.UTC)
+		local, _ = m.LocalObservationTime()
+		So(local.Format("15:04 MST"), ShouldEqual, "12:20 GMT")
+		loc, err := TAF{StationID: "KORD", Latitude: 41.98, Longitude: -87.90}.Location()
+		So(err, ShouldBeNil)
+		So(time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC).In(loc).Format("15:04 MST"), ShouldEqual, "07:00 CDT")
+		_, err = Station{StationID: "XXXX"}.Location()
+		So(err, ShouldNotBeNil)
+	})
+}
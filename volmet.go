@@ -0,0 +1,291 @@
+package addstogo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	trendTimeGroup   = regexp.MustCompile(`^(FM|TL|AT)(\d{4})$`)
+	vertVisGroup     = regexp.MustCompile(`^VV(\d{3})$`)
+	metricVisibility = regexp.MustCompile(`^\d{4}$`)
+	weatherGroup     = regexp.MustCompile(`^(\+|-|VC)?(MI|BC|PR|DR|BL|SH|TS|FZ)?((DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)+)?$`)
+	trendTimeWords   = map[string]string{"FM": "from", "TL": "until", "AT": "at"}
+	trendIndicators  = map[string]string{"BECMG": "becoming", "TEMPO": "temporarily"}
+	windSpeedUnits   = map[string]string{"KT": "knots", "MPS": "metres per second", "KMH": "kilometres per hour"}
+	volmetTrendStart = map[string]bool{"NOSIG": true, "BECMG": true, "TEMPO": true}
+)
+
+// VOLMETStation is a station of a VOLMET broadcast.
+type VOLMETStation struct {
+	ID string
+	// Name is the spoken station name, the identifier when empty.
+	Name string
+}
+
+// VOLMET assembles meteorological broadcasts for aircraft in flight from the latest reports of a list of stations.
+// A station's METAR is followed by its trend, or by a summary of its TAF when the METAR has none.
+type VOLMET struct {
+	Name     string
+	Stations []VOLMETStation
+	// TAFWindow is how far ahead of the observation the TAF summary reaches.
+	TAFWindow time.Duration
+
+	store    *Store
+	mu       sync.Mutex
+	bulletin string
+}
+
+// NewVOLMET returns a broadcast of the stations in order, with TAF summaries for the next six hours.
+func NewVOLMET(name string, stations ...VOLMETStation) *VOLMET {
+	return &VOLMET{Name: name, Stations: stations, TAFWindow: 6 * time.Hour, store: NewStore()}
+}
+
+// UpdateMETARs takes the reports of a response and tells whether the bulletin changed.
+func (v *VOLMET) UpdateMETARs(r *METARresponse) bool {
+	v.store.AddMETARs(r)
+	return v.regenerate()
+}
+
+// UpdateTAFs takes the forecasts of a response and tells whether the bulletin changed.
+func (v *VOLMET) UpdateTAFs(r *TAFresponse) bool {
+	v.store.AddTAFs(r)
+	return v.regenerate()
+}
+
+// Bulletin returns the current bulletin.
+func (v *VOLMET) Bulletin() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.bulletin
+}
+
+func (v *VOLMET) regenerate() bool {
+	var latest time.Time
+	var sections []string
+	for _, s := range v.Stations {
+		name := s.Name
+		if name == "" {
+			name = s.ID
+		}
+		m, ok := v.store.LatestMETAR(s.ID)
+		if !ok {
+			sections = append(sections, name+" not available.")
+			continue
+		}
+		if m.ObservationTime.After(latest) {
+			latest = m.ObservationTime
+		}
+		sentences := []string{fmt.Sprintf("%s %s", name, spokenTime(m.ObservationTime))}
+		sentences = append(sentences, spokenObservation(m, m.Wind())...)
+		if trend := spokenTrend(m.RawText); len(trend) > 0 {
+			sentences = append(sentences, trend...)
+		} else if t, ok := v.store.TAFAt(s.ID, m.ObservationTime); ok {
+			sentences = append(sentences, v.spokenTAF(t, m)...)
+		}
+		sections = append(sections, capitalized(sentences))
+	}
+	header := v.Name + " VOLMET"
+	if !latest.IsZero() {
+		header += ", " + spokenTime(latest)
+	}
+	bulletin := header + ".\n" + strings.Join(sections, "\n")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	changed := bulletin != v.bulletin
+	v.bulletin = bulletin
+	return changed
+}
+
+// capitalized joins the sentences with their first letters in upper case.
+func capitalized(sentences []string) string {
+	for i, s := range sentences {
+		if s != "" {
+			sentences[i] = strings.ToUpper(s[:1]) + s[1:]
+		}
+	}
+	return strings.Join(sentences, ". ") + "."
+}
+
+// spokenTrend reads the trend of a raw METAR, NOSIG or its BECMG and TEMPO groups.
+func spokenTrend(raw string) []string {
+	var sentences, words []string
+	trend, timing := false, false
+	flush := func() {
+		if len(words) > 0 {
+			sentences = append(sentences, strings.Join(words, ", "))
+			words = nil
+		}
+	}
+	for _, t := range strings.Fields(raw) {
+		if t == "RMK" {
+			break
+		}
+		trend = trend || volmetTrendStart[t]
+		if !trend {
+			continue
+		}
+		if indicator, ok := trendIndicators[t]; ok {
+			flush()
+			words, timing = []string{indicator}, true
+			continue
+		}
+		if t == "NOSIG" {
+			flush()
+			sentences = append(sentences, "NOSIG")
+			continue
+		}
+		w := spokenTrendGroup(t)
+		switch {
+		case w == "":
+		case timing && trendTimeGroup.MatchString(t):
+			// time groups belong to the change indicator before them
+			words[len(words)-1] += " " + w
+		default:
+			words, timing = append(words, w), false
+		}
+	}
+	flush()
+	return sentences
+}
+
+// spokenTrendGroup reads a group of a trend, or returns an empty string for groups it does not know.
+func spokenTrendGroup(t string) string {
+	switch {
+	case t == "CAVOK":
+		return "CAV OK"
+	case t == "NSW":
+		return "no significant weather"
+	case t == "NSC":
+		return "no significant cloud"
+	case trendTimeGroup.MatchString(t):
+		g := trendTimeGroup.FindStringSubmatch(t)
+		return trendTimeWords[g[1]] + " " + spokenDigits(g[2])
+	case windGroup.MatchString(t):
+		unit := "KT"
+		for u := range windSpeedUnits {
+			if strings.HasSuffix(t, u) {
+				unit = u
+			}
+		}
+		group := strings.TrimSuffix(t, unit)
+		dir, _ := strconv.Atoi(group[:3])
+		speed, gust := group[3:], ""
+		if i := strings.Index(speed, "G"); i >= 0 {
+			speed, gust = speed[:i], speed[i+1:]
+		}
+		s, _ := strconv.Atoi(speed)
+		g, _ := strconv.Atoi(gust)
+		return strings.ToLower(spokenWind(newWind(dir, s, g), windSpeedUnits[unit]))
+	case metricVisibility.MatchString(t):
+		if t == "9999" {
+			return "visibility one zero kilometres or more"
+		}
+		m, _ := strconv.Atoi(t)
+		return strings.ToLower(spokenMetres(m))
+	case vertVisGroup.MatchString(t):
+		ft, _ := strconv.Atoi(t[2:])
+		return "vertical visibility " + spokenHundreds(ft*100) + " feet"
+	case cloudGroup.MatchString(t):
+		g := cloudGroup.FindStringSubmatch(t)
+		ft, _ := strconv.Atoi(g[2])
+		clouds := spokenClouds([]SkyCondition{{SkyCover: g[1], CloudBaseFtAgl: ft * 100, CloudType: g[3]}}, 0, "")
+		return strings.ToLower(clouds[0])
+	case weatherGroup.MatchString(t) && strings.Trim(t, "+-") != "":
+		return English.describeWeather(t)
+	}
+	return ""
+}
+
+// spokenTAF summarizes the periods of the forecast from the observation to the end of the window.
+func (v *VOLMET) spokenTAF(t TAF, m METAR) []string {
+	from, to := m.ObservationTime, m.ObservationTime.Add(v.TAFWindow)
+	// groups without SM are in metres
+	metric, found := visibilityInMetres(t.RawText)
+	if !found {
+		metric, found = visibilityInMetres(m.RawText)
+	}
+	metric = metric || !found
+	sentences := []string{"Forecast until " + spokenTime(to)}
+	for _, f := range t.Forecast {
+		if !f.FcstTimeFrom.Before(to) || !f.FcstTimeTo.After(from) {
+			continue
+		}
+		var words []string
+		between := fmt.Sprintf("between %s and %s", spokenTime(f.FcstTimeFrom), spokenTime(f.FcstTimeTo))
+		switch f.ChangeIndicator {
+		case "FM":
+			if f.FcstTimeFrom.After(from) {
+				words = append(words, "from "+spokenTime(f.FcstTimeFrom))
+			}
+		case "BECMG":
+			end := f.TimeBecoming
+			if end.IsZero() {
+				end = f.FcstTimeTo
+			}
+			// a change completed before the observation is the prevailing forecast
+			if end.After(from) {
+				words = append(words, fmt.Sprintf("becoming between %s and %s", spokenTime(f.FcstTimeFrom), spokenTime(end)))
+			}
+		case "TEMPO":
+			words = append(words, "temporarily "+between)
+		case "":
+		default:
+			words = append(words, between)
+		}
+		if f.Probability != "" {
+			if len(words) == 0 {
+				words = append(words, between)
+			}
+			words[0] = "probability " + spokenDigits(f.Probability) + " " + words[0]
+		}
+		if f.WindSpeedKt > 0 || f.WindDirDegrees > 0 {
+			words = append(words, strings.ToLower(spokenWind(f.Wind(), "knots")))
+		}
+		cavok := false
+		for _, layer := range f.SkyCondition {
+			cavok = cavok || layer.SkyCover == "CAVOK"
+		}
+		if cavok {
+			words = append(words, "CAV OK")
+		} else if f.VisibilityStatuteMi > 0 {
+			words = append(words, strings.ToLower(spokenVisibility(decimal(f.VisibilityStatuteMi), metric)))
+		}
+		if f.WxString != "" {
+			words = append(words, English.describeWeather(f.WxString))
+		}
+		if !cavok {
+			for _, c := range spokenClouds(f.SkyCondition, f.VertVisFt, "") {
+				words = append(words, strings.ToLower(c))
+			}
+		}
+		if len(words) > 0 {
+			sentences = append(sentences, strings.Join(words, ", "))
+		}
+	}
+	if len(sentences) == 1 {
+		return nil
+	}
+	return sentences
+}
+
+// Feed polls the sources every interval and calls onBulletin with every new bulletin until the context is done.
+// A non-positive interval is an error.
+func (v *VOLMET) Feed(ctx context.Context, metars METARSource, tafs TAFSource, interval time.Duration,
+	onBulletin func(string), onError func(error)) error {
+	w := NewWatcher(metars, interval)
+	w.TAFSource = tafs
+	w.OnError = onError
+	w.Store = v.store
+	return w.RunReports(ctx, func([]METARChange, []TAF) {
+		// the watcher also drops the reports it will not see again
+		if v.regenerate() && onBulletin != nil {
+			onBulletin(v.Bulletin())
+		}
+	})
+}
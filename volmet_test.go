@@ -0,0 +1,116 @@
+package addstogo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVOLMET(t *testing.T) {
+	ulli := METAR{
+		RawText:         "ULLI 100800Z 23007MPS 210V270 9999 FEW040 20/11 Q1022 R88/090060 NOSIG",
+		StationID:       "ULLI",
+		ObservationTime: time.Date(2019, 6, 10, 8, 0, 0, 0, time.UTC),
+		WindDirDegrees:  230, WindSpeedKt: 14, VisibilityStatuteMi: 6.21, TempC: 20, DewpointC: 11, AltimInHg: 30.18,
+		SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}},
+	}
+	urss := METAR{
+		RawText:         "URSS 070930Z VRB06G11MPS 9999 -TSRA BKN007 SCT030CB 19/13 Q1012",
+		StationID:       "URSS",
+		ObservationTime: time.Date(2019, 6, 7, 9, 30, 0, 0, time.UTC),
+		WindSpeedKt:     12, WindGustKt: 21, VisibilityStatuteMi: 6.21, WxString: "-TSRA",
+		SkyCondition: []SkyCondition{{SkyCover: "BKN", CloudBaseFtAgl: 700}, {SkyCover: "SCT", CloudBaseFtAgl: 3000}},
+		TempC:        19, DewpointC: 13, AltimInHg: 29.88,
+	}
+	day := time.Date(2019, 6, 7, 0, 0, 0, 0, time.UTC)
+	taf := TAF{
+		RawText:       "TAF URSS 070456Z 0706/0806 23005MPS 9999 FEW040 BECMG 0708/0709 28006G11MPS SCT030CB PROB30 TEMPO 0709/0717 -TSRA",
+		StationID:     "URSS",
+		IssueTime:     day.Add(4*time.Hour + 56*time.Minute),
+		ValidTimeFrom: day.Add(6 * time.Hour),
+		ValidTimeTo:   day.Add(30 * time.Hour),
+		Forecast: []Forecast{
+			{FcstTimeFrom: day.Add(6 * time.Hour), FcstTimeTo: day.Add(8 * time.Hour), WindDirDegrees: 230, WindSpeedKt: 10,
+				VisibilityStatuteMi: 6.21, SkyCondition: []SkyCondition{{SkyCover: "FEW", CloudBaseFtAgl: 4000}}},
+			{FcstTimeFrom: day.Add(8 * time.Hour), FcstTimeTo: day.Add(17 * time.Hour), TimeBecoming: day.Add(9 * time.Hour),
+				ChangeIndicator: "BECMG", WindDirDegrees: 280, WindSpeedKt: 12, WindGustKt: 21, VisibilityStatuteMi: 6.21,
+				SkyCondition: []SkyCondition{{SkyCover: "SCT", CloudBaseFtAgl: 3000, CloudType: "CB"}}},
+			{FcstTimeFrom: day.Add(9 * time.Hour), FcstTimeTo: day.Add(17 * time.Hour), ChangeIndicator: "TEMPO", Probability: "30",
+				WxString: "-TSRA"},
+			{FcstTimeFrom: day.Add(17 * time.Hour), FcstTimeTo: day.Add(30 * time.Hour), ChangeIndicator: "FM",
+				WindDirDegrees: 50, WindSpeedKt: 10, SkyCondition: []SkyCondition{{SkyCover: "CAVOK"}}},
+		},
+	}
+	Convey("Trends should be read in VOLMET phraseology", t, func() {
+		So(spokenTrend(ulli.RawText), ShouldResemble, []string{"NOSIG"})
+		So(spokenTrend(urss.RawText), ShouldBeNil)
+		So(spokenTrend("UUEE 100800Z 18005MPS 3000 BR BKN004 12/11 Q1012 BECMG FM0930 TL1030 24008G13MPS 9999 NSW SCT015 "+
+			"TEMPO 2000 -SHRA BKN008CB RMK QFE745"), ShouldResemble, []string{
+			"becoming from zero niner three zero until one zero three zero, wind two four zero at eight gusting one three metres per second, " +
+				"visibility one zero kilometres or more, no significant weather, clouds scattered one thousand five hundred feet",
+			"temporarily, visibility two thousand metres, light rain showers, clouds broken eight hundred feet cumulonimbus",
+		})
+	})
+	Convey("A bulletin should cover the stations in order", t, func() {
+		v := NewVOLMET("Baltic", VOLMETStation{"ULLI", "Pulkovo"}, VOLMETStation{"URSS", "Sochi"}, VOLMETStation{ID: "UUEE"})
+		So(v.UpdateTAFs(newTAFresponse([]TAF{taf})), ShouldBeTrue)
+		So(v.UpdateMETARs(newMETARresponse([]METAR{ulli, urss})), ShouldBeTrue)
+		So(v.Bulletin(), ShouldEqual, "Baltic VOLMET, zero eight zero zero.\n"+
+			"Pulkovo zero eight zero zero. Wind two three zero at one four knots, varying between two one zero and two seven zero. "+
+			"Visibility one zero kilometres or more. Clouds few four thousand feet. Temperature two zero, dew point one one. "+
+			"QNH one zero two two. NOSIG.\n"+
+			"Sochi zero niner three zero. Wind variable at one two gusting two one knots. Visibility one zero kilometres or more. "+
+			"Light thunderstorm with rain. Clouds broken seven hundred feet, scattered three thousand feet cumulonimbus. "+
+			"Temperature one niner, dew point one three. QNH one zero one two. Forecast until one five three zero. "+
+			"Wind two eight zero at one two gusting two one knots, visibility one zero kilometres or more, clouds scattered three thousand feet cumulonimbus. "+
+			"Probability three zero temporarily between zero niner zero zero and one seven zero zero, light thunderstorm with rain.\n"+
+			"UUEE not available.")
+		So(v.UpdateMETARs(newMETARresponse([]METAR{ulli, urss})), ShouldBeFalse)
+		later := urss
+		later.ObservationTime = later.ObservationTime.Add(6 * time.Hour)
+		later.RawText = "URSS 071530Z 05005MPS CAVOK 18/12 Q1012"
+		later.WindDirDegrees, later.WindSpeedKt, later.WindGustKt, later.WxString, later.SkyCondition = 50, 10, 0, "", nil
+		So(v.UpdateMETARs(newMETARresponse([]METAR{later})), ShouldBeTrue)
+		So(v.Bulletin(), ShouldContainSubstring, "Sochi one five three zero. Wind zero five zero at one zero knots. CAV OK. Temperature one niner")
+		So(v.Bulletin(), ShouldContainSubstring, "Forecast until two one three zero. Wind two eight zero at one two gusting two one knots, "+
+			"visibility one zero kilometres or more, clouds scattered three thousand feet cumulonimbus. "+
+			"Probability three zero temporarily between zero niner zero zero and one seven zero zero, light thunderstorm with rain. "+
+			"From one seven zero zero, wind zero five zero at one zero knots, CAV OK.")
+	})
+	Convey("Feed should announce new bulletins only", t, func() {
+		v := NewVOLMET("Baltic", VOLMETStation{ID: "ULLI"})
+		metars := func(context.Context) (*METARresponse, error) { return newMETARresponse([]METAR{ulli}), nil }
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var bulletins []string
+		err := v.Feed(ctx, metars, nil, 10*time.Millisecond, func(b string) { bulletins = append(bulletins, b) }, nil)
+		So(err, ShouldResemble, context.DeadlineExceeded)
+		So(len(bulletins), ShouldEqual, 1)
+		So(bulletins[0], ShouldStartWith, "Baltic VOLMET, zero eight zero zero.\nULLI zero eight zero zero. Wind")
+		So(v.Feed(context.Background(), metars, nil, 0, nil, nil), ShouldNotBeNil)
+	})
+	Convey("Feed should drop the stations the source no longer reports", t, func() {
+		v := NewVOLMET("Baltic", VOLMETStation{ID: "ULLI"}, VOLMETStation{ID: "ULMM"})
+		ulmm := ulli
+		ulmm.StationID, ulmm.RawText = "ULMM", strings.Replace(ulli.RawText, "ULLI 100800Z", "ULMM 100900Z", 1)
+		ulmm.ObservationTime = ulli.ObservationTime.Add(time.Hour)
+		polls := 0
+		metars := func(context.Context) (*METARresponse, error) {
+			if polls++; polls == 1 {
+				return newMETARresponse([]METAR{ulli}), nil
+			}
+			return newMETARresponse([]METAR{ulmm}), nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var bulletins []string
+		v.Feed(ctx, metars, nil, 10*time.Millisecond, func(b string) { bulletins = append(bulletins, b) }, nil)
+		So(len(bulletins), ShouldEqual, 2)
+		So(bulletins[0], ShouldEndWith, "\nULMM not available.")
+		So(bulletins[1], ShouldStartWith, "Baltic VOLMET, zero niner zero zero.\nULLI not available.\nULMM zero niner zero zero.")
+		So(v.Bulletin(), ShouldEqual, bulletins[1])
+	})
+}
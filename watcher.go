@@ -0,0 +1,307 @@
+package addstogo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+// METARSource fetches the current set of observations.
+type METARSource func(ctx context.Context) (*METARresponse, error)
+
+// TAFSource fetches the current set of forecasts.
+type TAFSource func(ctx context.Context) (*TAFresponse, error)
+
+// URLSource fetches observations over HTTP, e.g. from the dataserver, the api/data endpoints or a cache file URL.
+// A nil client means http.DefaultClient.
+func URLSource(url string, client *http.Client) METARSource {
+	return func(ctx context.Context) (*METARresponse, error) {
+		input, err := fetchURL(ctx, url, client)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMETARs(input)
+	}
+}
+
+// FileSource re-reads observations from a local file, e.g. a downloaded cache file.
+func FileSource(path string) METARSource {
+	return func(ctx context.Context) (*METARresponse, error) {
+		input, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMETARs(input)
+	}
+}
+
+// TAFURLSource fetches forecasts over HTTP. A nil client means http.DefaultClient.
+func TAFURLSource(url string, client *http.Client) TAFSource {
+	return func(ctx context.Context) (*TAFresponse, error) {
+		input, err := fetchURL(ctx, url, client)
+		if err != nil {
+			return nil, err
+		}
+		return decodeTAFs(input)
+	}
+}
+
+// TAFFileSource re-reads forecasts from a local file.
+func TAFFileSource(path string) TAFSource {
+	return func(ctx context.Context) (*TAFresponse, error) {
+		input, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decodeTAFs(input)
+	}
+}
+
+func fetchURL(ctx context.Context, url string, client *http.Client) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("addstogo: %s: %s", url, resp.Status)
+	}
+	return readMaybeGzipped(resp.Body)
+}
+
+func readFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readMaybeGzipped(f)
+}
+
+func readMaybeGzipped(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	input, err := ioutil.ReadAll(r)
+	return bytes.TrimSpace(input), err
+}
+
+// decodeMETARs decodes dataserver XML, IWXXM, JSON or GeoJSON.
+func decodeMETARs(input []byte) (*METARresponse, error) {
+	switch {
+	case len(input) == 0:
+		return newMETARresponse(nil), nil
+	case input[0] == '[':
+		return UnmarshalMetarsJSON(input)
+	case input[0] == '{':
+		return UnmarshalMetarsGeoJSON(input)
+	case bytes.Contains(input, []byte("http://icao.int/iwxxm/")):
+		return UnmarshalIWXXMMetars(input)
+	}
+	return UnmarshalMetars(input)
+}
+
+// decodeTAFs decodes dataserver XML, IWXXM, JSON or GeoJSON.
+func decodeTAFs(input []byte) (*TAFresponse, error) {
+	switch {
+	case len(input) == 0:
+		return newTAFresponse(nil), nil
+	case input[0] == '[':
+		return UnmarshalTafsJSON(input)
+	case input[0] == '{':
+		return UnmarshalTafsGeoJSON(input)
+	case bytes.Contains(input, []byte("http://icao.int/iwxxm/")):
+		return UnmarshalIWXXMTafs(input)
+	}
+	return UnmarshalTafs(input)
+}
+
+// ChangeKind tells why a report was delivered by the watcher.
+type ChangeKind int
+
+const (
+	// NewReport is a routine observation not seen before.
+	NewReport ChangeKind = iota
+	// SpecialReport is a SPECI not seen before.
+	SpecialReport
+	// CorrectedReport is a correction of an observation seen before.
+	CorrectedReport
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case NewReport:
+		return "new"
+	case SpecialReport:
+		return "special"
+	case CorrectedReport:
+		return "corrected"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// METARChange is a new or changed observation.
+type METARChange struct {
+	Kind  ChangeKind
+	METAR METAR
+	// Previous is the replaced observation of a CorrectedReport.
+	Previous *METAR
+}
+
+// Watcher polls a source and delivers only the observations it has not seen yet.
+type Watcher struct {
+	Source METARSource
+	// TAFSource, when set, is polled by PollTAFs and RunReports.
+	TAFSource TAFSource
+	Interval  time.Duration
+	// OnError is called with every failed fetch. The watcher keeps polling.
+	OnError func(error)
+	// Store keeps the reports seen so far. It may be replaced with a shared store before the first poll.
+	Store *Store
+}
+
+// NewWatcher returns a watcher polling the source every interval.
+func NewWatcher(source METARSource, interval time.Duration) *Watcher {
+	return &Watcher{Source: source, Interval: interval, Store: NewStore()}
+}
+
+// Poll fetches the source once and returns the changes since the previous poll.
+// On the first poll every observation is new. A nil source returns no changes.
+func (w *Watcher) Poll(ctx context.Context) ([]METARChange, error) {
+	if w.Source == nil {
+		return nil, nil
+	}
+	r, err := w.Source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var changes []METARChange
+	var oldest time.Time
+	for _, m := range r.Data.METAR {
+		if oldest.IsZero() || m.ObservationTime.Before(oldest) {
+			oldest = m.ObservationTime
+		}
+		previous, known := w.Store.METARAt(m.StationID, m.ObservationTime)
+		switch w.Store.AddMETAR(m) {
+		case Added:
+			kind := NewReport
+			if m.MetarType == "SPECI" {
+				kind = SpecialReport
+			}
+			changes = append(changes, METARChange{Kind: kind, METAR: m})
+		case Replaced:
+			if known {
+				changes = append(changes, METARChange{Kind: CorrectedReport, METAR: m, Previous: &previous})
+			}
+		}
+	}
+	// reports older than any the source still returns will not be seen again
+	if !oldest.IsZero() {
+		w.Store.PruneMETARs(oldest)
+	}
+	return changes, nil
+}
+
+// PollTAFs fetches the TAF source once and returns the forecasts issued or amended since the previous poll.
+// Forecasts that expired before the latest issue time are dropped. A nil source returns no forecasts.
+func (w *Watcher) PollTAFs(ctx context.Context) ([]TAF, error) {
+	if w.TAFSource == nil {
+		return nil, nil
+	}
+	r, err := w.TAFSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var latest time.Time
+	for _, t := range r.Data.TAF {
+		if t.IssueTime.After(latest) {
+			latest = t.IssueTime
+		}
+	}
+	var fresh []TAF
+	for _, t := range r.Data.TAF {
+		if t.ValidTimeTo.After(latest) && w.Store.AddTAF(t) != Ignored {
+			fresh = append(fresh, t)
+		}
+	}
+	if !latest.IsZero() {
+		w.Store.PruneTAFs(latest)
+	}
+	return fresh, nil
+}
+
+// Run polls until the context is done, calling fn with every change, and returns the context error.
+func (w *Watcher) Run(ctx context.Context, fn func(METARChange)) error {
+	return w.RunReports(ctx, func(changes []METARChange, tafs []TAF) {
+		for _, c := range changes {
+			fn(c)
+		}
+	})
+}
+
+// RunReports polls both sources until the context is done, calling fn after every round
+// with the observation changes and the fresh forecasts, and returns the context error.
+// It returns an error without polling when the interval is not positive.
+func (w *Watcher) RunReports(ctx context.Context, fn func([]METARChange, []TAF)) error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("addstogo: watcher interval %v is not positive", w.Interval)
+	}
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		changes, err := w.Poll(ctx)
+		w.report(ctx, err)
+		tafs, err := w.PollTAFs(ctx)
+		w.report(ctx, err)
+		fn(changes, tafs)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) report(ctx context.Context, err error) {
+	if err != nil && w.OnError != nil && ctx.Err() == nil {
+		w.OnError(err)
+	}
+}
+
+// Watch polls in the background until the context is done and delivers the changes on the channel,
+// which is closed when the watcher stops, at once when the interval is not positive.
+func (w *Watcher) Watch(ctx context.Context) <-chan METARChange {
+	ch := make(chan METARChange)
+	go func() {
+		defer close(ch)
+		w.Run(ctx, func(c METARChange) {
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch
+}
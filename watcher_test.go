@@ -0,0 +1,155 @@
+package addstogo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const watcherFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<response version="1.2">
+<data num_results="2">
+<METAR><raw_text>URSS 070900Z 05004MPS CAVOK 18/12 Q1012</raw_text><station_id>URSS</station_id><observation_time>2019-06-07T09:00:00Z</observation_time><metar_type>METAR</metar_type></METAR>
+<METAR><raw_text>ULLI 070900Z 27003MPS 9999 SCT030 15/08 Q1015</raw_text><station_id>ULLI</station_id><observation_time>2019-06-07T09:00:00Z</observation_time><metar_type>METAR</metar_type></METAR>
+</data>
+</response>`
+
+func TestWatcher(t *testing.T) {
+	t0 := time.Date(2019, 6, 7, 9, 0, 0, 0, time.UTC)
+	Convey("Watcher over a changing source", t, func() {
+		batches := [][]METAR{
+			{{StationID: "URSS", ObservationTime: t0, RawText: "URSS 070900Z", MetarType: "METAR"}},
+			{{StationID: "URSS", ObservationTime: t0, RawText: "URSS 070900Z", MetarType: "METAR"},
+				{StationID: "URSS", ObservationTime: t0.Add(10 * time.Minute), RawText: "URSS 070910Z", MetarType: "SPECI"}},
+			{{StationID: "URSS", ObservationTime: t0, RawText: "URSS 070900Z COR", MetarType: "METAR", QualityControlFlags: QualityControlFlags{Corrected: true}},
+				{StationID: "URSS", ObservationTime: t0.Add(10 * time.Minute), RawText: "URSS 070910Z", MetarType: "SPECI"}},
+		}
+		poll := 0
+		w := NewWatcher(func(ctx context.Context) (*METARresponse, error) {
+			if poll >= len(batches) {
+				return nil, errors.New("source exhausted")
+			}
+			poll++
+			return newMETARresponse(batches[poll-1]), nil
+		}, time.Millisecond)
+
+		Convey("should deliver only new, special and corrected reports", func() {
+			changes, err := w.Poll(context.Background())
+			So(err, ShouldBeNil)
+			So(len(changes), ShouldEqual, 1)
+			So(changes[0].Kind, ShouldEqual, NewReport)
+
+			changes, _ = w.Poll(context.Background())
+			So(len(changes), ShouldEqual, 1)
+			So(changes[0].Kind, ShouldEqual, SpecialReport)
+			So(changes[0].Kind.String(), ShouldEqual, "special")
+
+			changes, _ = w.Poll(context.Background())
+			So(len(changes), ShouldEqual, 1)
+			So(changes[0].Kind, ShouldEqual, CorrectedReport)
+			So(changes[0].METAR.RawText, ShouldEqual, "URSS 070900Z COR")
+			So(changes[0].Previous.RawText, ShouldEqual, "URSS 070900Z")
+
+			_, err = w.Poll(context.Background())
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should stream changes until cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var kinds []ChangeKind
+			for c := range w.Watch(ctx) {
+				kinds = append(kinds, c.Kind)
+				if len(kinds) == 3 {
+					cancel()
+				}
+			}
+			So(kinds, ShouldResemble, []ChangeKind{NewReport, SpecialReport, CorrectedReport})
+		})
+		Convey("should return the context error from Run", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			count := 0
+			So(w.Run(ctx, func(METARChange) { count++ }), ShouldResemble, context.DeadlineExceeded)
+			So(count, ShouldEqual, 3)
+		})
+		Convey("should refuse a non-positive interval", func() {
+			for _, interval := range []time.Duration{0, -time.Second} {
+				w.Interval = interval
+				count := 0
+				So(w.Run(context.Background(), func(METARChange) { count++ }), ShouldNotBeNil)
+				So(count, ShouldEqual, 0)
+				_, open := <-w.Watch(context.Background())
+				So(open, ShouldBeFalse)
+			}
+		})
+	})
+	Convey("Watcher over a forecast source", t, func() {
+		current := TAF{StationID: "URSS", IssueTime: t0, ValidTimeFrom: t0, ValidTimeTo: t0.Add(24 * time.Hour), RawText: "TAF URSS 070900Z"}
+		next := TAF{StationID: "URSS", IssueTime: t0.Add(25 * time.Hour), ValidTimeFrom: t0.Add(25 * time.Hour), ValidTimeTo: t0.Add(49 * time.Hour), RawText: "TAF URSS 081000Z"}
+		batches := [][]TAF{{current}, {current}, {current, next}}
+		poll := 0
+		w := NewWatcher(nil, time.Millisecond)
+		w.TAFSource = func(ctx context.Context) (*TAFresponse, error) {
+			poll++
+			return newTAFresponse(batches[poll-1]), nil
+		}
+
+		Convey("should deliver only fresh forecasts and drop expired ones", func() {
+			changes, err := w.Poll(context.Background())
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+			tafs, err := w.PollTAFs(context.Background())
+			So(err, ShouldBeNil)
+			So(len(tafs), ShouldEqual, 1)
+			tafs, _ = w.PollTAFs(context.Background())
+			So(tafs, ShouldBeEmpty)
+			tafs, _ = w.PollTAFs(context.Background())
+			So(len(tafs), ShouldEqual, 1)
+			So(tafs[0].RawText, ShouldEqual, "TAF URSS 081000Z")
+			So(w.Store.TAFs("URSS", time.Time{}, t0.Add(48*time.Hour)), ShouldResemble, []TAF{next})
+		})
+	})
+	Convey("Sources should decode the fetched reports", t, func() {
+		Convey("from a gzipped cache file", func() {
+			dir, _ := ioutil.TempDir("", "addstogo")
+			defer os.RemoveAll(dir)
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			zw.Write([]byte(watcherFixture))
+			zw.Close()
+			name := filepath.Join(dir, "metars.cache.xml.gz")
+			So(ioutil.WriteFile(name, buf.Bytes(), 0644), ShouldBeNil)
+			r, err := FileSource(name)(context.Background())
+			So(err, ShouldBeNil)
+			So(r.Data.METAR[1].StationID, ShouldEqual, "ULLI")
+		})
+		Convey("over HTTP", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/json" {
+					w.Write([]byte(`[{"icaoId":"URSS","obsTime":1559898000,"rawOb":"URSS 070900Z"}]`))
+					return
+				}
+				w.Write([]byte(watcherFixture))
+			}))
+			defer server.Close()
+			r, err := URLSource(server.URL, nil)(context.Background())
+			So(err, ShouldBeNil)
+			So(r.Data.NumResults, ShouldEqual, 2)
+			r, err = URLSource(server.URL+"/json", nil)(context.Background())
+			So(err, ShouldBeNil)
+			So(r.Data.METAR[0].ObservationTime.Equal(t0), ShouldBeTrue)
+			_, err = URLSource(server.URL+"/missing\x7f", nil)(context.Background())
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,238 @@
+// Package wmm computes the Earth's main magnetic field with the World Magnetic Model,
+// entirely offline, to convert between true and magnetic directions.
+package wmm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WGS 84 ellipsoid and the geomagnetic reference radius, in kilometres.
+const (
+	semiMajorAxis   = 6378.137
+	flattening      = 1 / 298.257223563
+	referenceRadius = 6371.2
+)
+
+// Model is a set of Gauss coefficients with their secular variation.
+type Model struct {
+	Name  string
+	Epoch float64
+	// degree is the highest degree n of the expansion
+	degree int
+	// g, h, gDot and hDot are indexed by n*(n+1)/2+m
+	g, h, gDot, hDot []float64
+}
+
+// Field is the magnetic field at a point in nanotesla, in the geodetic north, east and down directions.
+type Field struct {
+	X, Y, Z float64
+}
+
+// Declination returns the angle from true north to magnetic north in degrees, positive east.
+func (f Field) Declination() float64 {
+	return math.Atan2(f.Y, f.X) * 180 / math.Pi
+}
+
+// Inclination returns the dip of the field below the horizontal in degrees.
+func (f Field) Inclination() float64 {
+	return math.Atan2(f.Z, math.Hypot(f.X, f.Y)) * 180 / math.Pi
+}
+
+// Intensity returns the total field intensity in nanotesla.
+func (f Field) Intensity() float64 {
+	return math.Sqrt(f.X*f.X + f.Y*f.Y + f.Z*f.Z)
+}
+
+// WMM2020 is the embedded World Magnetic Model 2020, valid from 2020.0 to 2025.0.
+var WMM2020 = mustLoad(wmm2020)
+
+// WMM2025 is the embedded World Magnetic Model 2025, valid from 2025.0 to 2030.0.
+// Later dates are extrapolated with growing error; load a newer WMM.COF with Load where accuracy matters.
+var WMM2025 = mustLoad(wmm2025)
+
+func mustLoad(cof string) *Model {
+	m, err := Load(strings.NewReader(cof))
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func index(n, m int) int {
+	return n*(n+1)/2 + m
+}
+
+// Load reads a model in the WMM.COF format published by NOAA.
+func Load(r io.Reader) (*Model, error) {
+	s := bufio.NewScanner(r)
+	if !s.Scan() {
+		return nil, fmt.Errorf("wmm: empty coefficient file")
+	}
+	header := strings.Fields(s.Text())
+	if len(header) < 2 {
+		return nil, fmt.Errorf("wmm: invalid header %q", s.Text())
+	}
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("wmm: invalid epoch %q", header[0])
+	}
+	model := &Model{Name: header[1], Epoch: epoch}
+	type row struct {
+		n, m             int
+		g, h, gDot, hDot float64
+	}
+	var rows []row
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "9999") {
+			break
+		}
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("wmm: invalid line %q", s.Text())
+		}
+		var values [6]float64
+		for i, f := range fields {
+			if values[i], err = strconv.ParseFloat(f, 64); err != nil {
+				return nil, fmt.Errorf("wmm: invalid line %q", s.Text())
+			}
+		}
+		r := row{int(values[0]), int(values[1]), values[2], values[3], values[4], values[5]}
+		if r.n < 1 || r.m < 0 || r.m > r.n {
+			return nil, fmt.Errorf("wmm: invalid degree or order in %q", s.Text())
+		}
+		if r.n > model.degree {
+			model.degree = r.n
+		}
+		rows = append(rows, r)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if model.degree == 0 {
+		return nil, fmt.Errorf("wmm: no coefficients")
+	}
+	size := index(model.degree, model.degree) + 1
+	model.g, model.h = make([]float64, size), make([]float64, size)
+	model.gDot, model.hDot = make([]float64, size), make([]float64, size)
+	for _, r := range rows {
+		i := index(r.n, r.m)
+		model.g[i], model.h[i], model.gDot[i], model.hDot[i] = r.g, r.h, r.gDot, r.hDot
+	}
+	return model, nil
+}
+
+// DecimalYear returns the time as a year with a fraction, e.g. 2022.5 in the middle of 2022.
+func DecimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(t.Year()) + float64(t.Sub(start))/float64(end.Sub(start))
+}
+
+// FieldAt returns the field at the geodetic position in degrees, the height above the ellipsoid in kilometres,
+// and the decimal year.
+func (model *Model) FieldAt(lat, lon, heightKm, year float64) Field {
+	phi, lambda := lat*math.Pi/180, lon*math.Pi/180
+
+	// geodetic to geocentric spherical coordinates
+	e2 := flattening * (2 - flattening)
+	sinPhi, cosPhi := math.Sincos(phi)
+	rc := semiMajorAxis / math.Sqrt(1-e2*sinPhi*sinPhi)
+	p := (rc + heightKm) * cosPhi
+	z := (rc*(1-e2) + heightKm) * sinPhi
+	r := math.Hypot(p, z)
+	phiC := math.Asin(z / r)
+
+	// Schmidt semi-normalized associated Legendre functions of the colatitude and their derivatives
+	n := model.degree
+	size := index(n, n) + 1
+	pnm, dpnm := make([]float64, size), make([]float64, size)
+	cosT, sinT := math.Sin(phiC), math.Cos(phiC)
+	pnm[0] = 1
+	for k := 1; k <= n; k++ {
+		for m := 0; m <= k; m++ {
+			i := index(k, m)
+			switch {
+			case k == m && k == 1:
+				pnm[i], dpnm[i] = sinT, cosT
+			case k == m:
+				f := math.Sqrt(float64(2*k-1) / float64(2*k))
+				j := index(k-1, k-1)
+				pnm[i] = f * sinT * pnm[j]
+				dpnm[i] = f * (cosT*pnm[j] + sinT*dpnm[j])
+			default:
+				j := index(k-1, m)
+				kk, mm := float64(k), float64(m)
+				norm := math.Sqrt(kk*kk - mm*mm)
+				pnm[i] = (2*kk - 1) * cosT * pnm[j] / norm
+				dpnm[i] = (2*kk - 1) * (cosT*dpnm[j] - sinT*pnm[j]) / norm
+				if k-2 >= m {
+					l := index(k-2, m)
+					back := math.Sqrt((kk-1)*(kk-1)-mm*mm) / norm
+					pnm[i] -= back * pnm[l]
+					dpnm[i] -= back * dpnm[l]
+				}
+			}
+		}
+	}
+
+	// field in geocentric north, east and down
+	dt := year - model.Epoch
+	var bNorth, bEast, bDown float64
+	ratio := referenceRadius / r
+	power := ratio * ratio
+	for k := 1; k <= n; k++ {
+		power *= ratio
+		for m := 0; m <= k; m++ {
+			i := index(k, m)
+			g := model.g[i] + dt*model.gDot[i]
+			h := model.h[i] + dt*model.hDot[i]
+			sinM, cosM := math.Sincos(float64(m) * lambda)
+			bNorth += power * (g*cosM + h*sinM) * dpnm[i]
+			bDown -= float64(k+1) * power * (g*cosM + h*sinM) * pnm[i]
+			if sinT > 1e-10 {
+				bEast += power * float64(m) * (g*sinM - h*cosM) * pnm[i] / sinT
+			}
+		}
+	}
+
+	// rotate into the geodetic frame
+	psi := phiC - phi
+	sinPsi, cosPsi := math.Sincos(psi)
+	return Field{
+		X: bNorth*cosPsi - bDown*sinPsi,
+		Y: bEast,
+		Z: bNorth*sinPsi + bDown*cosPsi,
+	}
+}
+
+// Declination returns the magnetic declination in degrees, positive east, at the position,
+// the height above the ellipsoid in kilometres and the time.
+func (model *Model) Declination(lat, lon, heightKm float64, t time.Time) float64 {
+	return model.FieldAt(lat, lon, heightKm, DecimalYear(t)).Declination()
+}
+
+// ToMagnetic converts a true direction to magnetic with the declination, in (0, 360].
+func ToMagnetic(trueDeg, declination float64) float64 {
+	return normalize(trueDeg - declination)
+}
+
+// ToTrue converts a magnetic direction to true with the declination, in (0, 360].
+func ToTrue(magneticDeg, declination float64) float64 {
+	return normalize(magneticDeg + declination)
+}
+
+// normalize brings a direction into (0, 360], as north is reported as 360.
+func normalize(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg <= 0 {
+		deg += 360
+	}
+	return deg
+}
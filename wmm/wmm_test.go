@@ -0,0 +1,83 @@
+package wmm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWMM2020(t *testing.T) {
+	Convey("WMM2020 should reproduce the official test values", t, func() {
+		cases := []struct {
+			year, height, lat, lon float64
+			x, y, z, d, i          float64
+		}{
+			{2020, 0, 80, 0, 6570.4, -146.3, 54606.0, -1.28, 83.14},
+			{2020, 0, 0, 120, 39624.3, 109.9, -10932.5, 0.16, -15.42},
+			{2020, 0, -80, 240, 5940.6, 15772.1, -52480.8, 69.36, -72.20},
+			{2020, 100, 80, 0, 6261.8, -185.5, 52429.1, -1.70, 83.19},
+			{2020, 100, 0, 120, 37636.7, 104.9, -10474.8, 0.16, -15.55},
+			{2020, 100, -80, 240, 5744.9, 14799.5, -49969.4, 68.78, -72.37},
+			{2022.5, 0, 80, 0, 6529.9, 1.1, 54713.4, 0.01, 83.19},
+			{2022.5, 0, 0, 120, 39684.7, -42.2, -10809.5, -0.06, -15.24},
+			{2022.5, 0, -80, 240, 6016.5, 15776.7, -52251.6, 69.13, -72.09},
+		}
+		for _, c := range cases {
+			f := WMM2020.FieldAt(c.lat, c.lon, c.height, c.year)
+			So(f.X, ShouldAlmostEqual, c.x, 0.1)
+			So(f.Y, ShouldAlmostEqual, c.y, 0.1)
+			So(f.Z, ShouldAlmostEqual, c.z, 0.1)
+			So(f.Declination(), ShouldAlmostEqual, c.d, 0.01)
+			So(f.Inclination(), ShouldAlmostEqual, c.i, 0.01)
+		}
+		So(WMM2020.FieldAt(80, 0, 0, 2020).Intensity(), ShouldAlmostEqual, 55000.1, 0.1)
+	})
+	Convey("WMM2025 should reproduce the test values", t, func() {
+		cases := []struct {
+			year, height, lat, lon float64
+			x, y, z, d, i          float64
+		}{
+			{2025, 0, 80, 0, 6521.6, 145.9, 54791.5, 1.28, 83.21},
+			{2025, 0, 0, 120, 39677.8, -109.6, -10580.2, -0.16, -14.93},
+			{2025, 0, -80, 240, 6117.5, 15751.9, -52022.5, 68.78, -72.00},
+			{2025, 100, 80, 0, 6216.0, 92.4, 52598.8, 0.85, 83.26},
+			{2025, 100, 0, 120, 37688.6, -96.2, -10152.1, -0.15, -15.08},
+			{2025, 100, -80, 240, 5907.6, 14780.3, -49540.7, 68.21, -72.19},
+			{2027.5, 0, 80, 0, 6500.8, 294.5, 54869.4, 2.59, 83.24},
+			{2027.5, 0, 0, 120, 39701.6, -167.4, -10381.8, -0.24, -14.65},
+			{2027.5, 0, -80, 240, 6200.7, 15730.3, -51783.7, 68.49, -71.92},
+		}
+		for _, c := range cases {
+			f := WMM2025.FieldAt(c.lat, c.lon, c.height, c.year)
+			So(f.X, ShouldAlmostEqual, c.x, 0.1)
+			So(f.Y, ShouldAlmostEqual, c.y, 0.1)
+			So(f.Z, ShouldAlmostEqual, c.z, 0.1)
+			So(f.Declination(), ShouldAlmostEqual, c.d, 0.01)
+			So(f.Inclination(), ShouldAlmostEqual, c.i, 0.01)
+		}
+	})
+	Convey("Declination should take a time", t, func() {
+		So(DecimalYear(time.Date(2022, 7, 2, 12, 0, 0, 0, time.UTC)), ShouldAlmostEqual, 2022.5, 0.001)
+		So(WMM2020.Declination(0, 120, 0, time.Date(2022, 7, 2, 12, 0, 0, 0, time.UTC)), ShouldAlmostEqual, -0.06, 0.01)
+	})
+	Convey("Directions should convert between true and magnetic", t, func() {
+		So(ToMagnetic(10, 15.5), ShouldEqual, 354.5)
+		So(ToMagnetic(15.5, 15.5), ShouldEqual, 360)
+		So(ToTrue(354.5, 15.5), ShouldEqual, 10)
+		So(ToTrue(350, -10), ShouldEqual, 340)
+	})
+	Convey("Coefficient files should be validated", t, func() {
+		m, err := Load(strings.NewReader("2025.0 WMM-2025 11/13/2024\n  1  0  -29351.8       0.0       12.0        0.0\n"))
+		So(err, ShouldBeNil)
+		So(m.Name, ShouldEqual, "WMM-2025")
+		So(m.Epoch, ShouldEqual, 2025)
+		_, err = Load(strings.NewReader(""))
+		So(err, ShouldNotBeNil)
+		_, err = Load(strings.NewReader("2025.0 WMM\n 1 2 0 0 0 0\n"))
+		So(err, ShouldNotBeNil)
+		_, err = Load(strings.NewReader("2025.0 WMM\n 1 0 x 0 0 0\n"))
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,327 @@
+package addstogo
+
+// zoneTable maps ICAO location indicator prefixes to IANA time zones. A line holds comma-separated
+// prefixes, a zone and, for prefixes shared by several zones, reference points as latitude and longitude pairs.
+const zoneTable = `
+AG Pacific/Guadalcanal
+AN Pacific/Nauru
+AY Pacific/Port_Moresby
+BG America/Godthab 64.19 -51.68 61.16 -45.43 67.02 -50.69
+BG America/Thule 76.53 -68.70
+BG America/Scoresbysund 70.49 -21.97
+BG America/Danmarkshavn 76.77 -18.67
+BI Atlantic/Reykjavik
+BK Europe/Belgrade
+C America/St_Johns 47.62 -52.75 48.94 -54.57 48.54 -58.55
+C America/Goose_Bay 53.32 -60.42 52.93 -66.86
+C America/Halifax 44.65 -63.57 46.29 -63.12 45.32 -65.89 46.11 -64.68 46.16 -60.05 47.00 -65.56
+C America/Toronto 43.68 -79.63 45.32 -75.67 45.47 -73.74 46.79 -71.39 48.37 -89.32 46.63 -80.80 42.28 -82.96 49.41 -82.47 48.21 -78.83 50.29 -88.91 51.29 -80.61
+C America/Iqaluit 63.76 -68.56 70.49 -68.52
+C America/Winnipeg 49.91 -97.24 53.97 -101.09 56.80 -94.06 58.74 -94.07 49.79 -94.36 50.82 -91.90
+C America/Rankin_Inlet 62.81 -92.12 61.09 -94.07 64.30 -96.08
+C America/Regina 50.43 -104.67 52.17 -106.70 53.21 -105.67 49.15 -102.99 55.15 -105.26
+C America/Edmonton 53.31 -113.58 51.13 -114.01 62.46 -114.44 55.18 -118.89 56.65 -111.22 49.63 -112.80 60.84 -115.78 68.30 -133.48
+C America/Cambridge_Bay 69.11 -105.14
+C America/Dawson_Creek 55.74 -120.18 56.24 -120.74
+C America/Fort_Nelson 58.84 -122.60
+C America/Vancouver 49.19 -123.18 48.65 -123.43 53.89 -122.68 49.96 -119.38 50.70 -120.44 54.29 -130.44 49.38 -121.48 52.18 -122.05
+C America/Whitehorse 60.71 -135.07 64.04 -139.13
+DA Africa/Algiers
+DB Africa/Porto-Novo
+DF Africa/Ouagadougou
+DG Africa/Accra
+DI Africa/Abidjan
+DN Africa/Lagos
+DR Africa/Niamey
+DT Africa/Tunis
+DX Africa/Lome
+EB Europe/Brussels
+ED,ET Europe/Berlin
+EE Europe/Tallinn
+EF Europe/Helsinki
+EG Europe/London
+EH Europe/Amsterdam
+EI Europe/Dublin
+EK Europe/Copenhagen
+EKV Atlantic/Faroe
+EL Europe/Luxembourg
+EN Europe/Oslo
+EP Europe/Warsaw
+ES Europe/Stockholm
+EV Europe/Riga
+EY Europe/Vilnius
+FA Africa/Johannesburg
+FB Africa/Gaborone
+FC Africa/Brazzaville
+FD Africa/Mbabane
+FE Africa/Bangui
+FG Africa/Malabo
+FH Atlantic/St_Helena
+FI Indian/Mauritius
+FJ Indian/Chagos
+FK Africa/Douala
+FL Africa/Lusaka
+FMC Indian/Comoro
+FME Indian/Reunion
+FMM,FMN,FMS Indian/Antananarivo
+FN Africa/Luanda
+FO Africa/Libreville
+FP Africa/Sao_Tome
+FQ Africa/Maputo
+FS Indian/Mahe
+FT Africa/Ndjamena
+FV Africa/Harare
+FW Africa/Blantyre
+FX Africa/Maseru
+FY Africa/Windhoek
+FZ Africa/Kinshasa -4.39 15.44 0.02 18.29 -5.91 22.47
+FZ Africa/Lubumbashi -11.59 27.53 -2.31 28.81 0.52 25.16 -6.12 23.57 -1.67 29.24
+GA Africa/Bamako
+GB Africa/Banjul
+GC Atlantic/Canary
+GF Africa/Freetown
+GG Africa/Bissau
+GL Africa/Monrovia
+GM Africa/Casablanca
+GO Africa/Dakar
+GQ Africa/Nouakchott
+GU Africa/Conakry
+GV Atlantic/Cape_Verde
+HA Africa/Addis_Ababa
+HB Africa/Bujumbura
+HC Africa/Mogadishu
+HD Africa/Djibouti
+HE Africa/Cairo
+HH Africa/Asmara
+HJ Africa/Juba
+HK Africa/Nairobi
+HL Africa/Tripoli
+HR Africa/Kigali
+HS Africa/Khartoum
+HT Africa/Dar_es_Salaam
+HU Africa/Kampala
+K America/New_York 40.64 -73.78 42.36 -71.01 33.64 -84.43 28.43 -81.31 25.79 -80.29 35.21 -80.94 38.85 -77.04 42.94 -78.73 39.99 -82.89 41.41 -81.85 38.17 -85.74 35.81 -83.99 30.40 -84.35 39.87 -75.24 40.49 -80.23 32.90 -80.04 27.98 -82.53 44.47 -73.15 43.65 -70.31 37.51 -77.32 36.08 -79.94 35.04 -85.20 42.75 -73.80 43.11 -76.11 39.05 -84.66 38.37 -81.59 30.49 -81.69 26.54 -81.76
+K America/Detroit 42.21 -83.35 42.88 -85.52 46.48 -84.37 42.78 -84.59 44.74 -85.58
+K America/Indiana/Indianapolis 39.72 -86.29 40.98 -85.20 40.41 -86.94 39.14 -86.62
+K America/Chicago 41.98 -87.90 29.98 -95.34 32.90 -97.04 29.53 -98.47 30.19 -97.67 44.88 -93.22 39.30 -94.71 38.75 -90.37 36.12 -86.68 35.04 -89.98 29.99 -90.26 30.69 -88.24 30.47 -87.19 33.56 -86.75 32.31 -90.08 34.73 -92.22 35.39 -97.60 36.20 -95.89 41.30 -95.89 43.58 -96.74 46.92 -96.82 46.77 -100.75 42.95 -87.90 41.53 -93.66 37.65 -97.43 38.95 -95.66 43.14 -89.34 35.22 -101.71 31.94 -102.20 33.66 -101.82 27.77 -97.50 25.91 -97.43 31.81 -97.23 39.84 -89.68 40.66 -89.69 37.80 -89.01 38.04 -87.53 44.48 -88.13 46.84 -92.19 48.26 -101.28 45.45 -98.42 40.85 -96.76 37.18 -93.38 34.27 -88.77 31.32 -92.55 32.45 -93.83 29.78 -93.84 31.07 -86.47 30.21 -85.68
+K America/Denver 39.86 -104.67 40.79 -111.98 35.04 -106.61 31.81 -106.38 46.61 -111.98 45.81 -108.54 41.16 -104.81 44.05 -103.05 38.81 -104.70 47.48 -111.37 45.78 -111.15 42.91 -106.47 39.12 -108.53 37.15 -107.75 32.34 -106.77 33.31 -104.51 36.74 -108.23 41.31 -105.67 42.82 -108.73 46.92 -114.09 48.31 -114.26 38.75 -109.75 37.70 -113.10 41.20 -112.01 38.28 -104.50 44.84 -106.98
+K America/Boise 43.56 -116.22 42.48 -114.49 43.51 -112.07 42.91 -112.60
+K America/Phoenix 33.43 -112.01 32.12 -110.94 35.14 -111.67 34.65 -112.42 32.66 -114.61 35.26 -113.94 35.02 -110.72
+K America/Los_Angeles 33.94 -118.41 37.62 -122.37 32.73 -117.19 47.45 -122.31 45.59 -122.60 36.08 -115.15 38.70 -121.59 39.50 -119.77 47.62 -117.53 42.37 -122.87 44.12 -123.21 40.98 -124.11 36.78 -119.72 35.43 -119.06 34.43 -119.84 33.83 -116.51 44.25 -121.15 46.26 -119.12 48.79 -122.54 40.83 -115.79 46.37 -117.02 46.75 -117.11 37.36 -121.93 34.06 -117.60 35.24 -120.64 40.51 -122.29 41.78 -124.24
+LA Europe/Tirane
+LB Europe/Sofia
+LC Asia/Nicosia
+LD Europe/Zagreb
+LE Europe/Madrid
+LF Europe/Paris
+LG Europe/Athens
+LH Europe/Budapest
+LI Europe/Rome
+LJ Europe/Ljubljana
+LK Europe/Prague
+LL Asia/Jerusalem
+LM Europe/Malta
+LN Europe/Monaco
+LO Europe/Vienna
+LP Europe/Lisbon 38.77 -9.13 41.24 -8.68 37.01 -7.97
+LP Atlantic/Madeira 32.70 -16.78 33.07 -16.35
+LP Atlantic/Azores 37.74 -25.70 38.76 -27.09 38.52 -28.72 39.46 -31.13 36.97 -25.17
+LQ Europe/Sarajevo
+LR Europe/Bucharest
+LS Europe/Zurich
+LT Europe/Istanbul
+LU Europe/Chisinau
+LW Europe/Skopje
+LX Europe/Gibraltar
+LY Europe/Belgrade
+LZ Europe/Bratislava
+MB America/Grand_Turk
+MD America/Santo_Domingo
+MG America/Guatemala
+MH America/Tegucigalpa
+MK America/Jamaica
+MM America/Mexico_City 19.44 -99.07 20.52 -103.31 19.16 -96.19 16.76 -99.75 18.10 -94.58 17.00 -96.73 19.35 -98.93 21.04 -101.48 22.25 -97.87 16.56 -93.02 19.85 -101.03
+MM America/Monterrey 25.78 -100.11 25.55 -103.41 27.44 -99.57 25.55 -100.93
+MM America/Matamoros 25.77 -97.53 26.01 -98.24
+MM America/Cancun 21.04 -86.88 18.51 -88.33 20.52 -86.93
+MM America/Merida 20.94 -89.66 19.82 -90.50 18.65 -91.80
+MM America/Chihuahua 28.70 -105.96 24.12 -104.53
+MM America/Mazatlan 23.16 -106.27 24.07 -110.36 24.76 -107.47 25.69 -109.08 23.15 -109.72 21.42 -104.84
+MM America/Hermosillo 29.10 -111.05 27.97 -110.93 31.30 -110.97 27.39 -109.83
+MM America/Tijuana 32.54 -116.97 32.63 -115.24 31.79 -116.60
+MN America/Managua
+MP America/Panama
+MR America/Costa_Rica
+MS America/El_Salvador
+MT America/Port-au-Prince
+MU America/Havana
+MW America/Cayman
+MY America/Nassau
+MZ America/Belize
+NC Pacific/Rarotonga
+NF Pacific/Fiji
+NFT Pacific/Tongatapu
+NG Pacific/Tarawa
+NI Pacific/Niue
+NL Pacific/Wallis
+NS Pacific/Pago_Pago -14.33 -170.71
+NS Pacific/Apia -13.83 -172.01
+NT Pacific/Tahiti
+NV Pacific/Efate
+NW Pacific/Noumea
+NZ Pacific/Auckland
+NZCI Pacific/Chatham
+OA Asia/Kabul
+OB Asia/Bahrain
+OE Asia/Riyadh
+OI Asia/Tehran
+OJ Asia/Amman
+OK Asia/Kuwait
+OL Asia/Beirut
+OM Asia/Dubai
+OO Asia/Muscat
+OP Asia/Karachi
+OR Asia/Baghdad
+OS Asia/Damascus
+OT Asia/Qatar
+OY Asia/Aden
+PA,PF,PO,PP America/Anchorage 61.17 -150.00 64.82 -147.86 58.35 -134.58 71.29 -156.77 55.36 -131.71 64.51 -165.45 60.78 -161.84 57.75 -152.49 59.65 -151.48
+PA America/Adak 51.88 -176.65
+PG Pacific/Guam
+PH Pacific/Honolulu
+PK Pacific/Majuro
+PL Pacific/Kiritimati
+PM Pacific/Midway
+PT Pacific/Palau 7.37 134.54
+PT Pacific/Chuuk 7.46 151.84 9.50 138.08
+PT Pacific/Pohnpei 6.98 158.21
+PT Pacific/Kosrae 5.36 162.96
+PW Pacific/Wake
+RC Asia/Taipei
+RJ,RO Asia/Tokyo
+RK Asia/Seoul
+RP Asia/Manila
+SA America/Argentina/Buenos_Aires
+SB,SD,SI,SJ,SN,SS,SW America/Sao_Paulo -23.43 -46.47 -22.81 -43.25 -15.87 -47.92 -19.63 -43.97 -25.53 -49.18 -29.99 -51.17 -27.67 -48.55 -22.01 -47.13 -20.26 -40.29 -16.63 -49.22 -18.88 -48.23 -26.88 -48.65
+SB,SD,SI,SJ,SN,SS,SW America/Bahia -12.91 -38.33 -14.82 -39.03 -16.44 -39.08
+SB,SD,SI,SJ,SN,SS,SW America/Recife -8.13 -34.92 -7.15 -34.95 -9.36 -40.57
+SB,SD,SI,SJ,SN,SS,SW America/Maceio -9.51 -35.79 -10.98 -37.07
+SB,SD,SI,SJ,SN,SS,SW America/Fortaleza -3.78 -38.53 -5.77 -35.37 -2.59 -44.23 -5.06 -42.82
+SB,SD,SI,SJ,SN,SS,SW America/Araguaina -10.29 -48.36 -7.23 -48.24
+SB,SD,SI,SJ,SN,SS,SW America/Belem -1.38 -48.48 0.05 -51.07 -5.37 -49.14
+SB,SD,SI,SJ,SN,SS,SW America/Santarem -2.42 -54.79 -4.24 -56.00
+SB,SD,SI,SJ,SN,SS,SW America/Manaus -3.04 -60.05 -3.38 -64.72 -0.15 -66.99
+SB,SD,SI,SJ,SN,SS,SW America/Boa_Vista 2.84 -60.69
+SB,SD,SI,SJ,SN,SS,SW America/Cuiaba -15.65 -56.12 -16.59 -54.72 -10.87 -55.58
+SB,SD,SI,SJ,SN,SS,SW America/Campo_Grande -20.47 -54.67 -19.01 -57.67 -22.20 -54.93
+SB,SD,SI,SJ,SN,SS,SW America/Porto_Velho -8.71 -63.90 -10.87 -61.85
+SB,SD,SI,SJ,SN,SS,SW America/Rio_Branco -9.87 -67.89 -7.60 -72.77
+SB,SD,SI,SJ,SN,SS,SW America/Noronha -3.85 -32.42
+SC America/Santiago
+SCIP Pacific/Easter
+SE America/Guayaquil
+SEGS,SEST Pacific/Galapagos
+SF Atlantic/Stanley
+SG America/Asuncion
+SK America/Bogota
+SL America/La_Paz
+SM America/Paramaribo
+SO America/Cayenne
+SP America/Lima
+SU America/Montevideo
+SV America/Caracas
+SY America/Guyana
+TA America/Antigua
+TB America/Barbados
+TD America/Dominica
+TFF America/Martinique
+TFFR America/Guadeloupe
+TG America/Grenada
+TI America/St_Thomas
+TJ America/Puerto_Rico
+TK America/St_Kitts
+TL America/St_Lucia
+TN America/Curacao
+TNCA America/Aruba
+TQ America/Anguilla
+TR America/Montserrat
+TT America/Port_of_Spain
+TU America/Tortola
+TV America/St_Vincent
+TX Atlantic/Bermuda
+UA Asia/Almaty 43.35 77.04 49.67 73.33 51.02 71.47 42.36 69.48 52.19 77.07 50.04 82.49 44.71 78.44 54.78 69.18
+UA Asia/Aqtobe 50.25 57.21
+UA Asia/Aqtau 43.86 51.09
+UA Asia/Atyrau 47.12 51.82
+UA Asia/Oral 51.15 51.54
+UA Asia/Qyzylorda 44.71 65.59
+UA Asia/Qostanay 53.21 63.55
+UB Asia/Baku
+UC Asia/Bishkek
+UD Asia/Yerevan
+UG Asia/Tbilisi
+UK Europe/Kiev
+UM Europe/Minsk
+UMK Europe/Kaliningrad
+UT Asia/Tashkent 41.26 69.28 39.70 66.98 40.12 67.83 41.58 60.64 42.49 59.62 39.78 64.48 40.36 71.75 40.73 72.29
+UTA Asia/Ashgabat
+UTD Asia/Dushanbe
+U Europe/Moscow 55.97 37.41 59.80 30.26 43.45 39.96 45.03 39.17 64.60 40.72 68.78 32.75 55.61 49.28 61.65 50.85 47.26 39.82 51.81 39.23 56.23 43.78 53.21 45.02 57.56 40.16 61.89 34.15 44.22 43.08 43.08 44.65 42.82 47.65 45.00 33.98
+U Europe/Samara 53.50 50.16
+U Europe/Ulyanovsk 54.27 48.23
+U Europe/Saratov 51.71 46.17
+U Europe/Volgograd 48.78 44.35
+U Europe/Astrakhan 46.28 48.01
+U Europe/Kirov 58.50 49.35
+U Asia/Yekaterinburg 56.74 60.80 55.31 61.50 57.19 65.32 61.03 69.09 66.59 66.61 61.34 73.40 51.80 55.46 57.91 56.02 54.56 55.87 66.07 76.52 65.95 78.30 60.95 76.48 62.19 74.53
+U Asia/Omsk 54.97 73.31
+U Asia/Novosibirsk 55.01 82.65
+U Asia/Barnaul 53.36 83.54 51.97 85.83
+U Asia/Tomsk 56.38 85.21 58.35 82.80
+U Asia/Novokuznetsk 53.81 86.88 55.27 86.11
+U Asia/Krasnoyarsk 56.17 92.49 69.31 87.33 53.74 91.39 51.67 94.40 58.47 92.11 61.28 91.17 64.27 100.22
+U Asia/Irkutsk 52.27 104.39 56.37 101.70 51.81 107.44 56.86 105.73 57.77 108.06
+U Asia/Chita 52.03 113.31
+U Asia/Yakutsk 62.09 129.77 56.91 124.91 50.43 127.41 60.72 114.83 66.40 112.03 71.70 128.90 58.60 125.41
+U Asia/Vladivostok 43.40 132.15 48.53 135.19 50.41 136.93 67.55 133.39
+U Asia/Sakhalin 46.89 142.72 53.15 142.36
+U Asia/Magadan 59.91 150.72
+U Asia/Srednekolymsk 67.48 153.73 68.74 161.34 70.62 147.90
+U Asia/Kamchatka 53.17 158.45
+U Asia/Anadyr 64.73 177.74 64.38 -173.24 69.78 170.60
+VA,VE,VI,VO Asia/Kolkata
+VC Asia/Colombo
+VD Asia/Phnom_Penh
+VG Asia/Dhaka
+VH Asia/Hong_Kong
+VL Asia/Vientiane
+VM Asia/Macau
+VN Asia/Kathmandu
+VQ Asia/Thimphu
+VR Indian/Maldives
+VT Asia/Bangkok
+VV Asia/Ho_Chi_Minh
+VY Asia/Yangon
+WA,WI,WR Asia/Jakarta -6.13 106.66 3.64 98.88 -0.87 100.35 -2.90 104.70 -7.79 110.43 -7.38 112.79 -6.90 107.58 -5.24 105.18 1.12 104.12 -1.64 103.64
+WA,WI,WR Asia/Pontianak -0.15 109.40 -2.23 113.94 -2.50 112.68
+WA,WI,WR Asia/Makassar -5.06 119.55 -8.75 115.17 -1.27 116.89 1.55 124.93 -3.44 114.76 -8.76 116.28 -10.17 123.67 -0.92 119.91
+WA,WI,WR Asia/Jayapura -2.58 140.52 -0.89 134.05 -3.71 128.09 -0.93 131.12 -8.52 140.42 -4.53 136.89
+WB Asia/Kuching
+WBS Asia/Brunei
+WM Asia/Kuala_Lumpur
+WP Asia/Dili
+WS Asia/Singapore
+Y Australia/Sydney -33.95 151.18 -35.31 149.19 -32.80 151.84 -28.84 153.56 -30.32 153.12 -31.08 150.85 -33.38 149.13
+Y Australia/Melbourne -37.67 144.84 -38.04 144.47 -36.74 144.33
+Y Australia/Hobart -42.84 147.51 -41.55 147.21 -40.99 145.73
+Y Australia/Brisbane -27.38 153.12 -19.25 146.77 -16.89 145.75 -21.17 149.18 -23.38 150.48 -20.66 139.49 -28.16 153.50 -26.60 153.09
+Y Australia/Adelaide -34.95 138.53 -32.51 137.72 -37.75 140.79 -29.04 134.72
+Y Australia/Darwin -12.41 130.88 -23.81 133.90 -19.63 134.18 -14.52 132.38
+Y Australia/Perth -31.94 115.97 -20.38 118.63 -17.95 122.23 -28.80 114.71 -30.79 121.46 -34.94 117.81 -23.17 117.75 -15.78 128.71
+Y Australia/Broken_Hill -32.00 141.47
+Y Australia/Lord_Howe -31.54 159.08
+ZB,ZG,ZH,ZJ,ZL,ZP,ZS,ZU,ZW,ZY Asia/Shanghai
+ZK Asia/Pyongyang
+ZM Asia/Ulaanbaatar 47.84 106.77 49.66 100.10 43.59 104.43
+ZM Asia/Hovd 47.95 91.63 49.97 89.92 50.07 91.94
+`